@@ -32,6 +32,20 @@ func (a *Application) SetContainer(container inter.Container) {
 	a.container = &container
 }
 
+// Copy returns an application with a copy of the container. Bindings made in
+// the copy are not visible in this application and vice versa.
+func (a *Application) Copy() inter.App {
+	copier, ok := (*a.container).(interface{ Copy() inter.Container })
+	if !ok {
+		panic(errors.New("container %T can't be copied", *a.container))
+	}
+
+	app := Application{}
+	app.SetContainer(copier.Copy())
+
+	return &app
+}
+
 // Singleton registered a shared binding in the container.
 func (a *Application) Singleton(abstract interface{}, concrete interface{}) {
 	(*a.container).Singleton(abstract, concrete)
@@ -0,0 +1,63 @@
+package console
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/http/maintenance"
+	"io/ioutil"
+	"strings"
+)
+
+// AppDown puts the application in maintenance mode. All requests get
+// status 503 until app:up is called.
+type AppDown struct {
+	Retry  int    `flag:"retry" description:"The number of seconds after which the request may be retried"`
+	Secret string `flag:"secret" description:"The path to visit to get a cookie that bypasses maintenance mode"`
+	Allow  string `flag:"allow" description:"Comma separated IP addresses or CIDRs that may access the application"`
+	Status int    `flag:"status" description:"The status code of the response (default 503)"`
+	View   string `flag:"view" description:"The template file to render instead of the error response"`
+}
+
+// Name of the command
+func (a AppDown) Name() string {
+	return "app:down"
+}
+
+// Description of the command
+func (a AppDown) Description() string {
+	return "Put the application into maintenance mode."
+}
+
+// Handle contains the logic of the command
+func (a AppDown) Handle(c inter.Cli) inter.ExitCode {
+	mode := maintenance.Mode{
+		Retry:  a.Retry,
+		Secret: strings.Trim(a.Secret, "/"),
+		Status: a.Status,
+	}
+	for _, address := range strings.Split(a.Allow, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			mode.Allow = append(mode.Allow, address)
+		}
+	}
+
+	if a.View != "" {
+		template, err := ioutil.ReadFile(a.View)
+		if err != nil {
+			c.Error("View could not be read: %s", err)
+			return inter.Failure
+		}
+		mode.Template = string(template)
+	}
+
+	if err := maintenance.Down(maintenance.Path(c.App()), mode); err != nil {
+		c.Error("Application could not be put into maintenance mode: %s", err)
+		return inter.Failure
+	}
+
+	c.Info("Application is now in maintenance mode.")
+	if mode.Secret != "" {
+		c.Line("Visit /%s to bypass maintenance mode.", mode.Secret)
+	}
+
+	return inter.Success
+}
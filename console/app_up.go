@@ -0,0 +1,31 @@
+package console
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/http/maintenance"
+)
+
+// AppUp brings the application out of maintenance mode
+type AppUp struct{}
+
+// Name of the command
+func (a AppUp) Name() string {
+	return "app:up"
+}
+
+// Description of the command
+func (a AppUp) Description() string {
+	return "Bring the application out of maintenance mode."
+}
+
+// Handle contains the logic of the command
+func (a AppUp) Handle(c inter.Cli) inter.ExitCode {
+	if err := maintenance.Up(maintenance.Path(c.App())); err != nil {
+		c.Error("Application could not be brought up: %s", err)
+		return inter.Failure
+	}
+
+	c.Info("Application is now live.")
+
+	return inter.Success
+}
@@ -0,0 +1,78 @@
+package console
+
+import (
+	"encoding/json"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/http/openapi"
+	"github.com/confetti-framework/foundation/http/routing"
+	"io/ioutil"
+)
+
+// OpenApiGenerate writes an OpenAPI 3.1 document of all registered routes.
+// Describe the routes with Describe, Accepts and Returns.
+type OpenApiGenerate struct {
+	Output  string `flag:"output" short:"o" description:"The file to write the document to (default openapi.json)"`
+	Version string `flag:"version" description:"The version of the API (default 1.0.0)"`
+}
+
+// Name of the command
+func (o OpenApiGenerate) Name() string {
+	return "openapi:generate"
+}
+
+// Description of the command
+func (o OpenApiGenerate) Description() string {
+	return "Generate an OpenAPI document of the routes."
+}
+
+// Handle contains the logic of the command
+func (o OpenApiGenerate) Handle(c inter.Cli) inter.ExitCode {
+	rawRoutes, err := c.App().MakeE("routes")
+	if err != nil {
+		c.Error("No routes found: %s", err)
+		return inter.Failure
+	}
+
+	routes := rawRoutes.(inter.RouteCollection)
+	if collection, ok := routes.(*routing.RouteCollection); ok {
+		collection.Compile()
+	}
+
+	document := openapi.Generate(routes.All(), openapi.Info{Title: o.title(c), Version: o.version()})
+	result, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		c.Error("Could not encode the document: %s", err)
+		return inter.Failure
+	}
+
+	if err = ioutil.WriteFile(o.output(), result, 0644); err != nil {
+		c.Error("Could not write the document: %s", err)
+		return inter.Failure
+	}
+
+	c.Info("OpenAPI document written to %s", o.output())
+
+	return inter.Success
+}
+
+func (o OpenApiGenerate) title(c inter.Cli) string {
+	name, err := c.App().MakeE("config.App.Name")
+	if err != nil {
+		return "API"
+	}
+	return name.(string)
+}
+
+func (o OpenApiGenerate) version() string {
+	if o.Version == "" {
+		return "1.0.0"
+	}
+	return o.Version
+}
+
+func (o OpenApiGenerate) output() string {
+	if o.Output == "" {
+		return "openapi.json"
+	}
+	return o.Output
+}
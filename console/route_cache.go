@@ -0,0 +1,53 @@
+package console
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/http/routing"
+)
+
+// RouteCache writes the compiled routes to a file, so the routes don't
+// have to be decorated and validated when the application boots.
+type RouteCache struct {
+	Path string `flag:"path" description:"The file to write the routes to (default storage/framework/routes.json)"`
+}
+
+// Name of the command
+func (r RouteCache) Name() string {
+	return "route:cache"
+}
+
+// Description of the command
+func (r RouteCache) Description() string {
+	return "Create a route cache file for faster route registration."
+}
+
+// Handle contains the logic of the command
+func (r RouteCache) Handle(c inter.Cli) inter.ExitCode {
+	rawRoutes, err := c.App().MakeE("routes")
+	if err != nil {
+		c.Error("No routes found: %s", err)
+		return inter.Failure
+	}
+
+	routes, ok := rawRoutes.(*routing.RouteCollection)
+	if !ok {
+		c.Error("Only a *routing.RouteCollection can be cached")
+		return inter.Failure
+	}
+
+	if err = routes.WriteCache(cachePath(r.Path)); err != nil {
+		c.Error("Routes could not be cached: %s", err)
+		return inter.Failure
+	}
+
+	c.Info("Routes cached successfully.")
+
+	return inter.Success
+}
+
+func cachePath(path string) string {
+	if path == "" {
+		return routing.DefaultCachePath
+	}
+	return path
+}
@@ -0,0 +1,34 @@
+package console
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"os"
+)
+
+// RouteClear removes the file written by route:cache
+type RouteClear struct {
+	Path string `flag:"path" description:"The route cache file (default storage/framework/routes.json)"`
+}
+
+// Name of the command
+func (r RouteClear) Name() string {
+	return "route:clear"
+}
+
+// Description of the command
+func (r RouteClear) Description() string {
+	return "Remove the route cache file."
+}
+
+// Handle contains the logic of the command
+func (r RouteClear) Handle(c inter.Cli) inter.ExitCode {
+	err := os.Remove(cachePath(r.Path))
+	if err != nil && !os.IsNotExist(err) {
+		c.Error("Route cache could not be removed: %s", err)
+		return inter.Failure
+	}
+
+	c.Info("Route cache cleared.")
+
+	return inter.Success
+}
@@ -0,0 +1,162 @@
+package console
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/http/middleware"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"strings"
+)
+
+// RouteList shows all registered routes.
+type RouteList struct {
+	Method    string `flag:"method" description:"Filter the routes by method"`
+	RouteName string `flag:"name" description:"Filter the routes by name"`
+	Path      string `flag:"path" description:"Filter the routes by path"`
+	Json      bool   `flag:"json" description:"Output the routes as JSON"`
+}
+
+type routeRow struct {
+	Methods    []string `json:"methods"`
+	Uri        string   `json:"uri"`
+	Domain     string   `json:"domain"`
+	Name       string   `json:"name"`
+	Action     string   `json:"action"`
+	Middleware []string `json:"middleware"`
+}
+
+// Name of the command
+func (l RouteList) Name() string {
+	return "route:list"
+}
+
+// Description of the command
+func (l RouteList) Description() string {
+	return "List all registered routes."
+}
+
+// Handle contains the logic of the command
+func (l RouteList) Handle(c inter.Cli) inter.ExitCode {
+	rawRoutes, err := c.App().MakeE("routes")
+	if err != nil {
+		c.Error("No routes found: %s", err)
+		return inter.Failure
+	}
+
+	routes := rawRoutes.(inter.RouteCollection)
+	if collection, ok := routes.(*routing.RouteCollection); ok {
+		collection.Compile()
+	}
+
+	rows := l.filter(routeRows(routes.All()))
+
+	if l.Json {
+		return l.renderJson(c, rows)
+	}
+
+	if len(rows) == 0 {
+		c.Error("Your application doesn't have any routes matching the given criteria.")
+		return inter.Failure
+	}
+
+	t := c.Table()
+	t.AppendHeader(table.Row{"Method", "URI", "Domain", "Name", "Action", "Middleware"})
+	for _, row := range rows {
+		t.AppendRow(table.Row{
+			strings.Join(row.Methods, "|"),
+			row.Uri,
+			row.Domain,
+			row.Name,
+			row.Action,
+			strings.Join(row.Middleware, ", "),
+		})
+	}
+	t.Render()
+
+	return inter.Success
+}
+
+func (l RouteList) renderJson(c inter.Cli, rows []routeRow) inter.ExitCode {
+	result, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		c.Error("Could not encode routes: %s", err)
+		return inter.Failure
+	}
+	// Write without colors, so the output can be piped to other tools
+	_, _ = fmt.Fprintln(c.Writer(), string(result))
+
+	return inter.Success
+}
+
+func (l RouteList) filter(rows []routeRow) []routeRow {
+	//goland:noinspection GoPreferNilSlice
+	result := []routeRow{}
+	for _, row := range rows {
+		if l.Method != "" && !containsMethod(row.Methods, l.Method) {
+			continue
+		}
+		if l.RouteName != "" && !strings.Contains(row.Name, l.RouteName) {
+			continue
+		}
+		if l.Path != "" && !strings.Contains(row.Uri, l.Path) {
+			continue
+		}
+		result = append(result, row)
+	}
+
+	return result
+}
+
+// Routes registered together with multiple methods (e.g. GET and HEAD) are combined in one row
+func routeRows(routes []inter.Route) []routeRow {
+	var rows []routeRow
+	for _, route := range routes {
+		row := routeRow{
+			Methods:    []string{route.Method()},
+			Uri:        routing.DisplayTemplate(route),
+			Domain:     route.Domain(),
+			Name:       route.Name(),
+			Action:     routing.ControllerName(route.Controller()),
+			Middleware: middlewareNames(route.Middleware()),
+		}
+
+		last := len(rows) - 1
+		if last >= 0 && rows[last].sameRoute(row) {
+			rows[last].Methods = append(rows[last].Methods, route.Method())
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func (r routeRow) sameRoute(other routeRow) bool {
+	return r.Uri == other.Uri &&
+		r.Domain == other.Domain &&
+		r.Name == other.Name &&
+		r.Action == other.Action &&
+		strings.Join(r.Middleware, ",") == strings.Join(other.Middleware, ",")
+}
+
+func middlewareNames(middlewares []inter.HttpMiddleware) []string {
+	//goland:noinspection GoPreferNilSlice
+	result := []string{}
+	for _, item := range middlewares {
+		result = append(result, middleware.Name(item))
+	}
+
+	return result
+}
+
+func containsMethod(methods []string, method string) bool {
+	for _, item := range methods {
+		if strings.EqualFold(item, method) {
+			return true
+		}
+	}
+
+	return false
+}
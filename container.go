@@ -42,6 +42,22 @@ func NewTestApp(decorator func(container inter.Container) inter.Container) inter
 	return app
 }
 
+// Copy returns a container with the same bindings. Bindings added to the copy
+// are not visible in this container and vice versa, so the copy can be used
+// in another goroutine.
+func (c *Container) Copy() inter.Container {
+	result := NewContainer()
+	result.bootContainer = c.bootContainer
+	for abstract, concrete := range c.bindings {
+		result.bindings[abstract] = concrete
+	}
+	for abstract, concrete := range c.singletons {
+		result.singletons[abstract] = concrete
+	}
+
+	return result
+}
+
 // Determine if the given abstract type has been bound.
 func (c *Container) Bound(abstract string) bool {
 	_, bound := c.bindings[abstract]
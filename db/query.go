@@ -0,0 +1,37 @@
+package db
+
+import (
+	"context"
+	"github.com/confetti-framework/contract/inter"
+	"strconv"
+	"strings"
+)
+
+// Placeholders converts the ? placeholders of a query to the syntax of the
+// driver of the connection (e.g. $1 for PostgreSQL).
+func Placeholders(connection inter.Connection, query string) string {
+	if _, ok := connection.(*PostgreSQL); !ok {
+		return query
+	}
+
+	var result strings.Builder
+	number := 0
+	for _, char := range query {
+		if char == '?' {
+			number++
+			result.WriteString("$" + strconv.Itoa(number))
+			continue
+		}
+		result.WriteRune(char)
+	}
+
+	return result.String()
+}
+
+// Context returns a context with the query timeout of the connection
+func Context(connection inter.Connection) (context.Context, context.CancelFunc) {
+	if timeout := connection.Timeout(); timeout > 0 {
+		return context.WithTimeout(context.Background(), timeout)
+	}
+	return context.WithCancel(context.Background())
+}
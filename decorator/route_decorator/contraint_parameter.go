@@ -8,16 +8,24 @@ import (
 type ConstrainParameters struct{}
 
 func (c ConstrainParameters) Decorate(route inter.Route) inter.Route {
-	uri := route.Uri()
-	if !strings.Contains(uri, "}") {
-		return route
+	if strings.Contains(route.Uri(), "}") {
+		route.SetUri(c.constrain(route.Uri(), route.Constraint()))
 	}
 
-	for parameter, constrainRegex := range route.Constraint() {
+	// Parameters of the domain can be constrained as well
+	if strings.Contains(route.Domain(), "}") {
+		route.SetDomain(c.constrain(route.Domain(), route.Constraint()))
+	}
+
+	return route
+}
+
+func (c ConstrainParameters) constrain(template string, constraints map[string]string) string {
+	for parameter, constrainRegex := range constraints {
 		oldMatch := "{" + parameter + "}"
 		newMatch := "{" + parameter + ":" + constrainRegex + "}"
-		uri = strings.Replace(uri, oldMatch, newMatch, 10)
+		template = strings.Replace(template, oldMatch, newMatch, 10)
 	}
 
-	return route.SetUri(uri)
+	return template
 }
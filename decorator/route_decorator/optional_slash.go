@@ -21,9 +21,10 @@ func (o UriSuffixSlash) Decorate(route inter.Route) inter.Route {
 
 // Revert decorator
 func (o UriSuffixSlash) Revert(route inter.Route) inter.Route {
-	uri := route.Uri()
-
-	uri = strings.ReplaceAll(uri, optionalSlash, "")
+	return route.SetUri(o.RevertUri(route.Uri()))
+}
 
-	return route.SetUri(uri)
+// RevertUri removes the placeholder from the uri without changing the route
+func (o UriSuffixSlash) RevertUri(uri string) string {
+	return strings.ReplaceAll(uri, optionalSlash, "")
 }
@@ -7,3 +7,5 @@ import (
 )
 
 var EncodeError = errors.New("").Status(http.StatusInternalServerError).Level(log_level.EMERGENCY)
+
+var DecodeError = errors.New("can't decode request body").Status(http.StatusBadRequest).Level(log_level.DEBUG)
@@ -25,7 +25,7 @@ func (e ErrorsToHtml) EncodeThrough(app inter.App, object interface{}, _ []inter
 	err := errs[0]
 	if e.View != nil {
 		builder := app.Make("template_builder").(inter.TemplateBuilder)
-		return view_helper.ContentByView(e.View(app, err), builder)
+		return view_helper.ContentByView(e.View(app, err), builder, view_helper.TemplateFunctions(app))
 	}
 
 	return str.UpperFirst(fmt.Sprintf("%v", err)), nil
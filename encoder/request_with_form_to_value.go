@@ -2,11 +2,17 @@ package encoder
 
 import (
 	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/http/method"
 	"github.com/confetti-framework/support"
 )
 
 func RequestWithFormToValue(request inter.Request) support.Value {
 	source := request.Source()
+	// Go only parses the body of POST, PUT and PATCH requests. A POST request
+	// that is changed to DELETE by method spoofing has a form body as well.
+	if original, ok := request.(interface{ OriginalMethod() string }); ok && original.OriginalMethod() == method.Post {
+		source.Method = method.Post
+	}
 	if err := source.ParseForm(); err != nil {
 		return support.NewValue(err)
 	}
@@ -0,0 +1,25 @@
+package encoder
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/support"
+	"github.com/vmihailenco/msgpack/v5"
+	"io/ioutil"
+)
+
+// RequestWithMsgpackToValue converts the MessagePack body to a value. An
+// invalid body results in a DecodeError.
+func RequestWithMsgpackToValue(request inter.Request) support.Value {
+	rawBody, err := ioutil.ReadAll(request.Source().Body)
+	if err != nil {
+		return support.NewValue(err)
+	}
+
+	var result interface{}
+	if err := msgpack.Unmarshal(rawBody, &result); err != nil {
+		return support.NewValue(errors.WithStack(DecodeError.Wrap("invalid MessagePack: %s", err)))
+	}
+
+	return support.NewValue(result)
+}
@@ -0,0 +1,34 @@
+package encoder
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/support"
+	"github.com/tidwall/gjson"
+	"io/ioutil"
+	"strings"
+)
+
+// RequestWithNdjsonToValue converts newline delimited JSON to a collection
+// with a value for each line. Empty lines are skipped, an invalid line results
+// in a DecodeError with the line number.
+func RequestWithNdjsonToValue(request inter.Request) support.Value {
+	rawBody, err := ioutil.ReadAll(request.Source().Body)
+	if err != nil {
+		return support.NewValue(err)
+	}
+
+	result := []interface{}{}
+	for i, line := range strings.Split(string(rawBody), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !gjson.Valid(line) {
+			return support.NewValue(errors.WithStack(DecodeError.Wrap("invalid JSON on line %d", i+1)))
+		}
+		result = append(result, gjson.Parse(line).Value())
+	}
+
+	return support.NewValue(result)
+}
@@ -0,0 +1,16 @@
+package encoder
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/support"
+	"io/ioutil"
+)
+
+func RequestWithTextToValue(request inter.Request) support.Value {
+	rawBody, err := ioutil.ReadAll(request.Source().Body)
+	if err != nil {
+		return support.NewValue(err)
+	}
+
+	return support.NewValue(string(rawBody))
+}
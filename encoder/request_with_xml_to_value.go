@@ -0,0 +1,93 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/support"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// RequestWithXmlToValue converts the XML body to a value. The root element is
+// omitted, child elements become keys, repeated elements become a collection
+// and attributes are prefixed with an @. Invalid XML results in a DecodeError.
+func RequestWithXmlToValue(request inter.Request) support.Value {
+	rawBody, err := ioutil.ReadAll(request.Source().Body)
+	if err != nil {
+		return support.NewValue(err)
+	}
+
+	return XmlToValue(string(rawBody))
+}
+
+func XmlToValue(raw string) support.Value {
+	decoder := xml.NewDecoder(bytes.NewBufferString(raw))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return support.NewValue(nil)
+		}
+		if err != nil {
+			return support.NewValue(errors.WithStack(DecodeError.Wrap("invalid XML: %s", err)))
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			result, err := decodeXmlElement(decoder, start)
+			if err != nil {
+				return support.NewValue(errors.WithStack(DecodeError.Wrap("invalid XML: %s", err)))
+			}
+			return support.NewValue(result)
+		}
+	}
+}
+
+func decodeXmlElement(decoder *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	children := map[string]interface{}{}
+	for _, attr := range start.Attr {
+		children["@"+attr.Name.Local] = attr.Value
+	}
+
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			child, err := decodeXmlElement(decoder, element)
+			if err != nil {
+				return nil, err
+			}
+			addXmlChild(children, element.Name.Local, child)
+		case xml.CharData:
+			text.Write(element)
+		case xml.EndElement:
+			content := strings.TrimSpace(text.String())
+			if len(children) == 0 {
+				return content, nil
+			}
+			if content != "" {
+				children["#text"] = content
+			}
+			return children, nil
+		}
+	}
+}
+
+// Repeated elements with the same name are collected in a slice
+func addXmlChild(children map[string]interface{}, name string, child interface{}) {
+	existing, ok := children[name]
+	if !ok {
+		children[name] = child
+		return
+	}
+	if collection, ok := existing.([]interface{}); ok {
+		children[name] = append(collection, child)
+		return
+	}
+	children[name] = []interface{}{existing, child}
+}
@@ -0,0 +1,25 @@
+package encoder
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/support"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+)
+
+// RequestWithYamlToValue converts the YAML body to a value. Invalid YAML
+// results in a DecodeError.
+func RequestWithYamlToValue(request inter.Request) support.Value {
+	rawBody, err := ioutil.ReadAll(request.Source().Body)
+	if err != nil {
+		return support.NewValue(err)
+	}
+
+	var result interface{}
+	if err := yaml.Unmarshal(rawBody, &result); err != nil {
+		return support.NewValue(errors.WithStack(DecodeError.Wrap("invalid YAML: %s", err)))
+	}
+
+	return support.NewValue(result)
+}
@@ -19,5 +19,5 @@ func (v ViewToHtml) EncodeThrough(app inter.App, object interface{}, _ []inter.E
 	}
 
 	builder := app.Make("template_builder").(inter.TemplateBuilder)
-	return view_helper.ContentByView(view, builder)
+	return view_helper.ContentByView(view, builder, view_helper.TemplateFunctions(app))
 }
@@ -0,0 +1,13 @@
+package auth
+
+import (
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/syslog/log_level"
+	net "net/http"
+)
+
+var UnauthenticatedError = errors.New("unauthenticated").Status(net.StatusUnauthorized).Level(log_level.DEBUG)
+var UserNotFoundError = UnauthenticatedError.Wrap("user not found")
+var InvalidCredentialsError = UnauthenticatedError.Wrap("invalid credentials")
+var GuardNotFoundError = errors.New("auth guard not found").Status(net.StatusInternalServerError).Level(log_level.ERROR)
+var ForbiddenError = errors.New("this action is unauthorized").Status(net.StatusForbidden).Level(log_level.DEBUG)
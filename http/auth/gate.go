@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Ability determines whether the user may perform an action
+type Ability func(user User, arguments ...interface{}) bool
+
+// BeforeHook is called before all abilities. Return decided true to skip the
+// abilities (e.g. to allow everything for super-admins).
+type BeforeHook func(user User, ability string, arguments ...interface{}) (allowed bool, decided bool)
+
+// Gate authorizes the actions of users by abilities and policies. Bind the
+// gate as "gate", so it can be used with request.Can() and middleware.Can.
+//
+//	gate := auth.NewGate().
+//		Define("view-dashboard", func(user auth.User, _ ...interface{}) bool { return true }).
+//		Policy(Post{}, PostPolicy{})
+//	app.Singleton("gate", gate)
+//
+// A policy has a method per ability. For a Post subject, ability "update"
+// calls the method Update(user auth.User, post Post) bool of the policy.
+type Gate struct {
+	abilities map[string]Ability
+	policies  map[reflect.Type]interface{}
+	before    []BeforeHook
+}
+
+func NewGate() *Gate {
+	return &Gate{abilities: map[string]Ability{}, policies: map[reflect.Type]interface{}{}}
+}
+
+func (g *Gate) Define(ability string, callback Ability) *Gate {
+	g.abilities[ability] = callback
+	return g
+}
+
+// Policy registers the policy for the type of the model
+func (g *Gate) Policy(model interface{}, policy interface{}) *Gate {
+	g.policies[indirectType(model)] = policy
+	return g
+}
+
+func (g *Gate) Before(hook BeforeHook) *Gate {
+	g.before = append(g.before, hook)
+	return g
+}
+
+// Allows determines whether the user may perform the ability. An ability
+// without definition or policy is denied.
+func (g *Gate) Allows(user User, ability string, arguments ...interface{}) bool {
+	if user == nil {
+		return false
+	}
+	for _, hook := range g.before {
+		if allowed, decided := hook(user, ability, arguments...); decided {
+			return allowed
+		}
+	}
+
+	if len(arguments) > 0 {
+		if allowed, found := g.callPolicy(user, ability, arguments); found {
+			return allowed
+		}
+	}
+	if callback, ok := g.abilities[ability]; ok {
+		return callback(user, arguments...)
+	}
+
+	return false
+}
+
+func (g *Gate) Denies(user User, ability string, arguments ...interface{}) bool {
+	return !g.Allows(user, ability, arguments...)
+}
+
+// Authorize returns ForbiddenError if the user may not perform the ability
+func (g *Gate) Authorize(user User, ability string, arguments ...interface{}) error {
+	if !g.Allows(user, ability, arguments...) {
+		return errors.WithStack(ForbiddenError.Wrap("ability '%s'", ability))
+	}
+	return nil
+}
+
+func (g *Gate) callPolicy(user User, ability string, arguments []interface{}) (bool, bool) {
+	if arguments[0] == nil {
+		return false, false
+	}
+	policy, ok := g.policies[indirectType(arguments[0])]
+	if !ok {
+		return false, false
+	}
+	method := reflect.ValueOf(policy).MethodByName(policyMethod(ability))
+	if !method.IsValid() {
+		return false, false
+	}
+
+	methodType := method.Type()
+	if methodType.NumIn() != len(arguments)+1 || methodType.NumOut() != 1 || methodType.Out(0).Kind() != reflect.Bool {
+		panic(errors.New("policy %T: %s must have a user and %d arguments and return a bool", policy, policyMethod(ability), len(arguments)))
+	}
+
+	in := []reflect.Value{reflect.ValueOf(user)}
+	if !in[0].Type().AssignableTo(methodType.In(0)) {
+		// The policy is meant for another type of user
+		return false, true
+	}
+	for i, argument := range arguments {
+		in = append(in, convertArgument(argument, methodType.In(i+1)))
+	}
+
+	return method.Call(in)[0].Bool(), true
+}
+
+// GateFromApp returns the gate bound as "gate". Without gate, all abilities are denied.
+func GateFromApp(app inter.AppReader) *Gate {
+	raw, err := app.MakeE("gate")
+	if err != nil {
+		return NewGate()
+	}
+	gate, ok := raw.(*Gate)
+	if !ok {
+		return NewGate()
+	}
+
+	return gate
+}
+
+// policyMethod converts an ability (e.g. view-any) to the method of a policy (e.g. ViewAny)
+func policyMethod(ability string) string {
+	parts := strings.FieldsFunc(ability, func(r rune) bool {
+		return r == '-' || r == '_' || r == ' ' || r == '.'
+	})
+	result := ""
+	for _, part := range parts {
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		result += string(runes)
+	}
+	return result
+}
+
+// convertArgument passes a pointer to a policy that expects a value and the other way around
+func convertArgument(argument interface{}, expected reflect.Type) reflect.Value {
+	value := reflect.ValueOf(argument)
+	if value.Type().AssignableTo(expected) {
+		return value
+	}
+	if value.Kind() == reflect.Ptr && value.Elem().Type().AssignableTo(expected) {
+		return value.Elem()
+	}
+	pointer := reflect.New(value.Type())
+	pointer.Elem().Set(value)
+	if pointer.Type().AssignableTo(expected) {
+		return pointer
+	}
+
+	panic(errors.New("can't pass %T to a policy that expects %s", argument, expected))
+}
+
+func indirectType(value interface{}) reflect.Type {
+	result := reflect.TypeOf(value)
+	for result != nil && result.Kind() == reflect.Ptr {
+		result = result.Elem()
+	}
+	return result
+}
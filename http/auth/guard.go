@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation/http/session"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+)
+
+// Guard determines the user of a request
+type Guard interface {
+	// User returns the user of the request or UnauthenticatedError
+	User(request inter.Request) (User, error)
+}
+
+// Challenger is a guard that tells the client how to authenticate with the
+// WWW-Authenticate header
+type Challenger interface {
+	Challenge() string
+}
+
+// Guards are the guards by name. Bind them as "auth_guards", so they can be
+// selected per route with middleware.Authenticate.
+//
+//	app.Bind("auth_guards", auth.Guards{
+//		"web": auth.SessionGuard{Provider: users},
+//		"api": auth.BearerGuard{Provider: users, Hash: true},
+//	})
+type Guards map[string]Guard
+
+// GuardByName resolves the guard from "auth_guards"
+func GuardByName(app inter.AppReader, name string) (Guard, error) {
+	raw, err := app.MakeE("auth_guards")
+	if err != nil {
+		return nil, errors.WithStack(GuardNotFoundError.Wrap("bind auth.Guards as auth_guards"))
+	}
+	guards, ok := raw.(Guards)
+	if !ok {
+		return nil, errors.WithStack(GuardNotFoundError.Wrap("auth_guards is not of type auth.Guards"))
+	}
+	guard, ok := guards[name]
+	if !ok {
+		return nil, errors.WithStack(GuardNotFoundError.Wrap("guard '%s'", name))
+	}
+
+	return guard, nil
+}
+
+// SessionGuard authenticates the user by the id in the session. Use Login and
+// Logout in your controllers.
+type SessionGuard struct {
+	Provider UserProvider
+}
+
+const sessionKey = "_auth.user_id"
+
+func (s SessionGuard) User(request inter.Request) (User, error) {
+	current, err := session.FromApp(request.App())
+	if err != nil {
+		return nil, err
+	}
+	id := current.GetOr(sessionKey, "").String()
+	if id == "" {
+		return nil, errors.WithStack(UnauthenticatedError)
+	}
+
+	return s.Provider.ById(id)
+}
+
+// Attempt validates the credentials and logs the user in
+func (s SessionGuard) Attempt(request inter.Request, credentials map[string]string) (User, error) {
+	user, err := byCredentials(s.Provider, credentials)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Provider.ValidateCredentials(user, credentials) {
+		return nil, errors.WithStack(InvalidCredentialsError)
+	}
+
+	return user, s.Login(request, user)
+}
+
+// Login stores the user in the session. The session id is regenerated to
+// prevent session fixation.
+func (s SessionGuard) Login(request inter.Request, user User) error {
+	current, err := session.FromApp(request.App())
+	if err != nil {
+		return err
+	}
+	current.Regenerate().Put(sessionKey, user.AuthIdentifier())
+	request.App().Bind("user", user)
+
+	return nil
+}
+
+// Logout removes all data from the session
+func (s SessionGuard) Logout(request inter.Request) error {
+	current, err := session.FromApp(request.App())
+	if err != nil {
+		return err
+	}
+	current.Invalidate()
+	request.App().Bind("user", nil)
+
+	return nil
+}
+
+// BearerGuard authenticates the user by the token of the Authorization header
+//
+//	Authorization: Bearer <token>
+type BearerGuard struct {
+	Provider UserProvider
+	// The column with the token. Default api_token
+	Column string
+	// Hash the token with SHA-256 before it's compared with the column
+	Hash bool
+}
+
+func (b BearerGuard) User(request inter.Request) (User, error) {
+	return userByToken(b.Provider, columnOr(b.Column, "api_token"), BearerToken(request), b.Hash)
+}
+
+func (b BearerGuard) Challenge() string {
+	return "Bearer"
+}
+
+// BearerToken returns the token of the Authorization header
+func BearerToken(request inter.Request) string {
+	header := request.Header("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+// BasicGuard authenticates the user with HTTP basic authentication
+type BasicGuard struct {
+	Provider UserProvider
+	// The column of the username. Default email
+	Column string
+	// Default Confetti
+	Realm string
+}
+
+func (b BasicGuard) User(request inter.Request) (User, error) {
+	source := request.Source()
+	username, password, ok := source.BasicAuth()
+	if !ok || username == "" {
+		return nil, errors.WithStack(UnauthenticatedError)
+	}
+
+	credentials := map[string]string{columnOr(b.Column, "email"): username, passwordKey: password}
+	user, err := byCredentials(b.Provider, credentials)
+	if err != nil {
+		return nil, err
+	}
+	if !b.Provider.ValidateCredentials(user, credentials) {
+		return nil, errors.WithStack(InvalidCredentialsError)
+	}
+
+	return user, nil
+}
+
+func (b BasicGuard) Challenge() string {
+	return `Basic realm="` + columnOr(b.Realm, "Confetti") + `", charset="UTF-8"`
+}
+
+// ApiKeyGuard authenticates the user by the key in a header or the query string
+type ApiKeyGuard struct {
+	Provider UserProvider
+	// Default X-Api-Key
+	Header string
+	// The query parameter with the key. The query string is not used if empty
+	Query string
+	// The column with the key. Default api_key
+	Column string
+	// Hash the key with SHA-256 before it's compared with the column
+	Hash bool
+}
+
+func (a ApiKeyGuard) User(request inter.Request) (User, error) {
+	key := request.Header(columnOr(a.Header, "X-Api-Key"))
+	if key == "" && a.Query != "" {
+		key = request.Source().URL.Query().Get(a.Query)
+	}
+
+	return userByToken(a.Provider, columnOr(a.Column, "api_key"), key, a.Hash)
+}
+
+// dummyHash is compared with the password of an unknown user
+var dummyHash = []byte("$2a$10$BsLMRANPcIwCx4Y3i3XqY.nN52aip5OpG1c6.jRqMQTkQRWv8k.KC")
+
+// byCredentials retrieves the user by the credentials. The password is also
+// compared if the user is not found, so the response time doesn't reveal
+// whether the user exists.
+func byCredentials(provider UserProvider, credentials map[string]string) (User, error) {
+	user, err := provider.ByCredentials(credentials)
+	if err != nil && errors.Is(err, UserNotFoundError) {
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(credentials[passwordKey]))
+	}
+
+	return user, err
+}
+
+func userByToken(provider UserProvider, column string, token string, hash bool) (User, error) {
+	if token == "" {
+		return nil, errors.WithStack(UnauthenticatedError)
+	}
+	if hash {
+		sum := sha256.Sum256([]byte(token))
+		token = hex.EncodeToString(sum[:])
+	}
+
+	return provider.ByCredentials(map[string]string{column: token})
+}
+
+func columnOr(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
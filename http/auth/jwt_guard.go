@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation/http/jwt"
+	"github.com/confetti-framework/support"
+)
+
+// JwtGuard authenticates the user by a JSON Web Token in the Authorization
+// header. The user is retrieved by the sub claim. Without provider, the user
+// is created from the claims. The claims are bound as "jwt_claims".
+type JwtGuard struct {
+	Jwt      jwt.Jwt
+	Provider UserProvider
+}
+
+func (j JwtGuard) User(request inter.Request) (User, error) {
+	token := BearerToken(request)
+	if token == "" {
+		return nil, errors.WithStack(UnauthenticatedError)
+	}
+
+	claims, err := j.Jwt.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Subject() == "" {
+		return nil, errors.WithStack(jwt.InvalidTokenError.Wrap("token has no subject"))
+	}
+	request.App().Bind("jwt_claims", claims)
+
+	if j.Provider == nil {
+		return GenericUser{IdColumn: "sub", Attributes: support.NewMap(map[string]interface{}(claims))}, nil
+	}
+
+	return j.Provider.ById(claims.Subject())
+}
+
+func (j JwtGuard) Challenge() string {
+	return "Bearer"
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation/db"
+	"golang.org/x/crypto/bcrypt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const passwordKey = "password"
+
+var validColumn = regexp.MustCompile("^[A-Za-z_][A-Za-z0-9_]*$")
+
+// SqlUserProvider retrieves the users from a database table. The passwords
+// must be hashed with bcrypt.
+//
+//	auth.NewSqlUserProvider(app.Db())
+type SqlUserProvider struct {
+	Database inter.Database
+	// Default users
+	Table string
+	// Default id
+	IdColumn string
+	// Default password
+	PasswordColumn string
+}
+
+func NewSqlUserProvider(database inter.Database) SqlUserProvider {
+	return SqlUserProvider{Database: database, Table: "users", IdColumn: "id", PasswordColumn: "password"}
+}
+
+func (s SqlUserProvider) ById(id string) (User, error) {
+	return s.first(map[string]string{s.idColumn(): id})
+}
+
+func (s SqlUserProvider) ByCredentials(credentials map[string]string) (User, error) {
+	conditions := map[string]string{}
+	for column, value := range credentials {
+		if column != passwordKey {
+			conditions[column] = value
+		}
+	}
+	if len(conditions) == 0 {
+		return nil, errors.WithStack(InvalidCredentialsError)
+	}
+
+	return s.first(conditions)
+}
+
+func (s SqlUserProvider) ValidateCredentials(user User, credentials map[string]string) bool {
+	generic, ok := user.(GenericUser)
+	if !ok {
+		return false
+	}
+	hash := generic.Get(s.passwordColumn()).String()
+
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(credentials[passwordKey])) == nil
+}
+
+func (s SqlUserProvider) first(conditions map[string]string) (User, error) {
+	var columns []string
+	for column := range conditions {
+		if !validColumn.MatchString(column) {
+			return nil, errors.WithStack(InvalidCredentialsError.Wrap("invalid column '%s'", column))
+		}
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	var where []string
+	var args []interface{}
+	for _, column := range columns {
+		where = append(where, column+" = ?")
+		args = append(args, conditions[column])
+	}
+
+	query := "SELECT * FROM " + s.table() + " WHERE " + strings.Join(where, " AND ") + " LIMIT 1"
+	rows, err := s.Database.QueryE(db.Placeholders(s.Database.Connection(), query), args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't retrieve user")
+	}
+	if rows.Empty() {
+		return nil, errors.WithStack(UserNotFoundError)
+	}
+
+	return GenericUser{IdColumn: s.idColumn(), Attributes: rows.First().Map()}, nil
+}
+
+func (s SqlUserProvider) table() string {
+	if s.Table == "" {
+		return "users"
+	}
+	return s.Table
+}
+
+func (s SqlUserProvider) idColumn() string {
+	if s.IdColumn == "" {
+		return "id"
+	}
+	return s.IdColumn
+}
+
+func (s SqlUserProvider) passwordColumn() string {
+	if s.PasswordColumn == "" {
+		return "password"
+	}
+	return s.PasswordColumn
+}
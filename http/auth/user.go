@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"fmt"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/support"
+)
+
+// User is an authenticated user
+type User interface {
+	// AuthIdentifier returns the unique identifier of the user (e.g. the primary key)
+	AuthIdentifier() string
+}
+
+// GenericUser is a user with the columns of a database row
+type GenericUser struct {
+	// The name of the unique identifier. Default id
+	IdColumn   string
+	Attributes support.Map
+}
+
+func (g GenericUser) AuthIdentifier() string {
+	column := g.IdColumn
+	if column == "" {
+		column = "id"
+	}
+	return fmt.Sprint(g.Attributes.Get(column).Raw())
+}
+
+func (g GenericUser) Get(key string) support.Value {
+	return g.Attributes.Get(key)
+}
+
+// FromApp returns the user that is authenticated by middleware.Authenticate
+func FromApp(app inter.AppReader) (User, error) {
+	raw, err := app.MakeE("user")
+	if err != nil {
+		return nil, errors.WithStack(UnauthenticatedError)
+	}
+	user, ok := raw.(User)
+	if !ok {
+		return nil, errors.WithStack(UnauthenticatedError)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,10 @@
+package auth
+
+// UserProvider retrieves the users for the guards
+type UserProvider interface {
+	ById(id string) (User, error)
+	// ByCredentials returns the user by the credentials. The password is
+	// ignored, use ValidateCredentials to check the password.
+	ByCredentials(credentials map[string]string) (User, error)
+	ValidateCredentials(user User, credentials map[string]string) bool
+}
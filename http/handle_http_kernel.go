@@ -3,7 +3,9 @@ package http
 import (
 	"github.com/confetti-framework/contract/inter"
 	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation/http/middleware"
 	net "net/http"
+	"strconv"
 	"strings"
 )
 
@@ -37,33 +39,85 @@ func HandleHttpKernel(app inter.App, response net.ResponseWriter, request *net.R
 
 	appRequest := NewRequest(Options{App: app, Source: *request})
 
+	appResponse := sendResponse(kernel, appRequest, response)
+
+	/*
+	   |--------------------------------------------------------------------------
+	   | Terminate The Middlewares
+	   |--------------------------------------------------------------------------
+	   |
+	   | The response is flushed to the client first, so the work of terminable
+	   | middlewares (e.g. logging) doesn't add latency to the response.
+	   |
+	*/
+	if flusher, ok := response.(net.Flusher); ok {
+		flusher.Flush()
+	}
+	middleware.Terminate(appRequest, appResponse)
+}
+
+// sendResponse handles the request and writes the response to the client
+func sendResponse(kernel inter.HttpKernel, appRequest inter.Request, response net.ResponseWriter) (appResponse inter.Response) {
 	defer func() {
 		if rec := recover(); rec != nil {
 			if err, ok := rec.(error); ok {
 				rec = errors.WithStack(err)
 			}
-			appResponse := kernel.RecoverFromMiddlewarePanic(rec)
+			appResponse = kernel.RecoverFromMiddlewarePanic(rec)
 			exposeResponse(response, appResponse)
 		}
 	}()
 
-	appResponse := kernel.Handle(appRequest)
+	appResponse = kernel.Handle(appRequest)
 
 	exposeResponse(response, appResponse)
+
+	return appResponse
 }
 
 func exposeResponse(response net.ResponseWriter, appResponse inter.Response) {
+	body := appResponse.GetBody()
+
+	// With the length, the client has the complete response when it is
+	// flushed, before the middlewares are terminated
+	bodyAllowed := bodyAllowedForStatus(appResponse.GetStatus())
+	if bodyAllowed {
+		appResponse.GetHeaders().Set("Content-Length", strconv.Itoa(len(body)))
+	}
+
 	// Add HTTP headers
 	for key, values := range appResponse.GetHeaders() {
 		response.Header().Add(key, strings.Join(values, "; "))
 	}
 
+	// Add cookies
+	for _, cookie := range appResponse.GetCookies() {
+		response.Header().Add("Set-Cookie", cookie.String())
+	}
+
 	// Add HTTP status
 	response.WriteHeader(appResponse.GetStatus())
 
 	// Add HTTP body
-	_, err := response.Write([]byte(appResponse.GetBody()))
+	if !bodyAllowed {
+		return
+	}
+	_, err := response.Write([]byte(body))
 	if err != nil {
 		panic(err)
 	}
 }
+
+// bodyAllowedForStatus reports whether a response with the status can have a
+// body and therefore a Content-Length (RFC 7230, section 3.3)
+func bodyAllowedForStatus(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == net.StatusNoContent:
+		return false
+	case status == net.StatusNotModified:
+		return false
+	}
+	return true
+}
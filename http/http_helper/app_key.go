@@ -0,0 +1,30 @@
+package http_helper
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/syslog/log_level"
+	"net/http"
+)
+
+// MinAppKeyLength is the minimum number of characters of config.App.Key
+const MinAppKeyLength = 32
+
+var InvalidAppKeyError = errors.New("config.App.Key must be a string of at least %d characters", MinAppKeyLength).
+	Status(http.StatusInternalServerError).
+	Level(log_level.EMERGENCY)
+
+// AppKey returns config.App.Key. An empty or short key is rejected, because
+// a signature or an encrypted value can be forged with a guessable key.
+func AppKey(app inter.AppReader) (string, error) {
+	raw, err := app.MakeE("config.App.Key")
+	if err != nil {
+		return "", errors.WithStack(InvalidAppKeyError.Wrap("%s", err))
+	}
+	key, ok := raw.(string)
+	if !ok || len(key) < MinAppKeyLength {
+		return "", errors.WithStack(InvalidAppKeyError)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,61 @@
+package http_helper
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+)
+
+var ErrInvalidCiphertext = errors.New("invalid ciphertext")
+
+// Encrypt encrypts and authenticates the value with AES-256-GCM. The key of
+// the cipher is derived from the given key (e.g. config.App.Key). The result
+// is URL safe, so it can be used as cookie value.
+func Encrypt(key string, value []byte) (string, error) {
+	aead, err := newCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, value, nil)), nil
+}
+
+// Decrypt decrypts a value encrypted by Encrypt. An error is returned if the
+// value has been changed or encrypted with another key.
+func Decrypt(key string, value string) ([]byte, error) {
+	aead, err := newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(raw) < aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+
+	result, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+
+	return result, nil
+}
+
+func newCipher(key string) (cipher.AEAD, error) {
+	hash := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(hash[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -6,14 +6,18 @@ import (
 )
 
 func MuxFromRoute(route inter.Route) *mux.Route {
+	return MuxFromTemplates(route.RouteOptions().Prefixes(), route.Uri(), route.Domain())
+}
+
+func MuxFromTemplates(prefixes []string, uri string, domain string) *mux.Route {
 	muxRoute := new(mux.Route)
-	for _, prefix := range route.RouteOptions().Prefixes() {
+	for _, prefix := range prefixes {
 		muxRoute.PathPrefix(prefix)
 	}
 
-	muxRoute.Path(route.Uri())
-	if route.Domain() != "" {
-		muxRoute.Host(route.Domain())
+	muxRoute.Path(uri)
+	if domain != "" {
+		muxRoute.Host(domain)
 	}
 
 	return muxRoute
@@ -0,0 +1,191 @@
+package http_helper
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// TrustAll can be used as trusted proxy to trust every remote address
+const TrustAll = "*"
+
+// Forwarded contains the values of one hop from the Forwarded
+// header (RFC 7239) or from the X-Forwarded-* headers.
+type Forwarded struct {
+	For   string
+	Proto string
+	Host  string
+	Port  string
+}
+
+// TrustedProxies converts the configured addresses and CIDRs to networks.
+// Invalid values are ignored.
+func TrustedProxies(proxies []string) []*net.IPNet {
+	var result []*net.IPNet
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == TrustAll {
+			_, ipv4, _ := net.ParseCIDR("0.0.0.0/0")
+			_, ipv6, _ := net.ParseCIDR("::/0")
+			result = append(result, ipv4, ipv6)
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				continue
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			result = append(result, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err == nil {
+			result = append(result, network)
+		}
+	}
+
+	return result
+}
+
+// IsTrustedProxy determines whether the address is within one of the trusted networks
+func IsTrustedProxy(address string, trusted []*net.IPNet) bool {
+	ip := net.ParseIP(StripPort(address))
+	if ip == nil {
+		return false
+	}
+	for _, network := range trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ForwardedHops returns the hops from the client to the last proxy. The Forwarded
+// header takes precedence over the X-Forwarded-* headers.
+func ForwardedHops(source http.Request) []Forwarded {
+	if values := source.Header.Values("Forwarded"); len(values) > 0 {
+		return parseForwarded(values)
+	}
+
+	var hops []Forwarded
+	for _, address := range headerList(source, "X-Forwarded-For") {
+		hops = append(hops, Forwarded{For: address})
+	}
+
+	// Every proxy appends to X-Forwarded-Proto, -Host and -Port, so the
+	// values are aligned with the last hops. Values sent by the client
+	// end up at the first hops.
+	proto := headerList(source, "X-Forwarded-Proto")
+	host := headerList(source, "X-Forwarded-Host")
+	port := headerList(source, "X-Forwarded-Port")
+	for len(hops) < len(proto) || len(hops) < len(host) || len(hops) < len(port) || len(hops) == 0 {
+		hops = append([]Forwarded{{}}, hops...)
+	}
+	for i := range hops {
+		hops[i].Proto = fromRight(proto, len(hops)-i)
+		hops[i].Host = fromRight(host, len(hops)-i)
+		hops[i].Port = fromRight(port, len(hops)-i)
+	}
+
+	return hops
+}
+
+// TrustedForwarded returns the values of the hops that are added by trusted
+// proxies, walking from the proxy closest to the application to the client.
+// A value of a proxy further away from the application takes precedence,
+// because that proxy received the original request. Hops added by the
+// client can't be trusted and are ignored.
+func TrustedForwarded(source http.Request, trusted []*net.IPNet) Forwarded {
+	if !IsTrustedProxy(source.RemoteAddr, trusted) {
+		return Forwarded{}
+	}
+
+	result := Forwarded{}
+	hops := ForwardedHops(source)
+	for i := len(hops) - 1; i >= 0; i-- {
+		// The hop is added by a trusted proxy
+		result.For = hops[i].For
+		result.Proto = orValue(hops[i].Proto, result.Proto)
+		result.Host = orValue(hops[i].Host, result.Host)
+		result.Port = orValue(hops[i].Port, result.Port)
+
+		// The previous hop is only added by a trusted proxy if the request
+		// was received from a trusted proxy
+		if !IsTrustedProxy(hops[i].For, trusted) {
+			break
+		}
+	}
+
+	return result
+}
+
+// StripPort removes the port and IPv6 brackets from an address
+func StripPort(address string) string {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return strings.Trim(address, "[]")
+	}
+	return host
+}
+
+func parseForwarded(values []string) []Forwarded {
+	var hops []Forwarded
+	for _, value := range values {
+		for _, element := range strings.Split(value, ",") {
+			hop := Forwarded{}
+			for _, pair := range strings.Split(element, ";") {
+				parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+				if len(parts) != 2 {
+					continue
+				}
+				pairValue := strings.Trim(parts[1], `"`)
+				switch strings.ToLower(parts[0]) {
+				case "for":
+					hop.For = pairValue
+				case "proto":
+					hop.Proto = strings.ToLower(pairValue)
+				case "host":
+					hop.Host = pairValue
+				}
+			}
+			hops = append(hops, hop)
+		}
+	}
+
+	return hops
+}
+
+func headerList(source http.Request, key string) []string {
+	var result []string
+	for _, value := range source.Header.Values(key) {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+	}
+
+	return result
+}
+
+// fromRight returns the value at the position from the right (starting with 1)
+func fromRight(values []string, position int) string {
+	if position > len(values) {
+		return ""
+	}
+	return values[len(values)-position]
+}
+
+func orValue(value string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,61 @@
+package http_helper
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	SignatureKey = "signature"
+	ExpiresKey   = "expires"
+)
+
+// Signature calculates the HMAC of the path and the query parameters. The
+// host and scheme are left out so that the signature remains valid behind
+// proxies and load balancers.
+func Signature(key string, path string, query url.Values) string {
+	query = copyValues(query)
+	query.Del(SignatureKey)
+
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(path + "?" + query.Encode()))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// HasValidSignature determines whether the signature in the query matches
+// the path and the other query parameters.
+func HasValidSignature(key string, path string, query url.Values) bool {
+	expected := Signature(key, path, query)
+	actual := query.Get(SignatureKey)
+
+	return actual != "" && hmac.Equal([]byte(expected), []byte(actual))
+}
+
+// SignatureExpired determines whether the expires parameter is in the past.
+// Links without expires parameter never expire.
+func SignatureExpired(query url.Values, now time.Time) bool {
+	raw := query.Get(ExpiresKey)
+	if raw == "" {
+		return false
+	}
+	expires, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return true
+	}
+
+	return now.Unix() > expires
+}
+
+func copyValues(values url.Values) url.Values {
+	result := url.Values{}
+	for key, items := range values {
+		result[key] = append([]string{}, items...)
+	}
+
+	return result
+}
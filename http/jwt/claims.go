@@ -0,0 +1,61 @@
+package jwt
+
+import "time"
+
+// Claims are the claims of the payload of a token
+type Claims map[string]interface{}
+
+func (c Claims) Subject() string {
+	subject, _ := c["sub"].(string)
+	return subject
+}
+
+func (c Claims) Issuer() string {
+	issuer, _ := c["iss"].(string)
+	return issuer
+}
+
+// Audience returns the aud claim, which can be a string or a list of strings
+func (c Claims) Audience() []string {
+	switch audience := c["aud"].(type) {
+	case string:
+		return []string{audience}
+	case []string:
+		return audience
+	case []interface{}:
+		var result []string
+		for _, item := range audience {
+			if item, ok := item.(string); ok {
+				result = append(result, item)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+func (c Claims) ExpiresAt() (time.Time, bool) {
+	return c.time("exp")
+}
+
+func (c Claims) NotBefore() (time.Time, bool) {
+	return c.time("nbf")
+}
+
+func (c Claims) IssuedAt() (time.Time, bool) {
+	return c.time("iat")
+}
+
+func (c Claims) time(key string) (time.Time, bool) {
+	switch value := c[key].(type) {
+	case float64:
+		return time.Unix(int64(value), 0), true
+	case int64:
+		return time.Unix(value, 0), true
+	case int:
+		return time.Unix(int64(value), 0), true
+	case time.Time:
+		return value, true
+	}
+	return time.Time{}, false
+}
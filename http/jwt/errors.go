@@ -0,0 +1,17 @@
+package jwt
+
+import (
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/syslog/log_level"
+	net "net/http"
+)
+
+var InvalidTokenError = errors.New("invalid token").Status(net.StatusUnauthorized).Level(log_level.DEBUG)
+var MalformedTokenError = InvalidTokenError.Wrap("malformed token")
+var InvalidSignatureError = InvalidTokenError.Wrap("invalid signature")
+var ExpiredTokenError = InvalidTokenError.Wrap("token has expired")
+var TokenNotValidYetError = InvalidTokenError.Wrap("token is not valid yet")
+var InvalidIssuerError = InvalidTokenError.Wrap("invalid issuer")
+var InvalidAudienceError = InvalidTokenError.Wrap("invalid audience")
+
+var InvalidKeyError = errors.New("invalid JWT key").Level(log_level.ERROR)
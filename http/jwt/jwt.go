@@ -0,0 +1,190 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/confetti-framework/errors"
+	"strings"
+	"time"
+)
+
+// Jwt issues and verifies JSON Web Tokens (RFC 7519)
+//
+//	service := jwt.Jwt{
+//		Keys:     keys,
+//		Issuer:   "https://confetti-framework.com",
+//		Audience: "mobile",
+//		Lifetime: time.Hour,
+//		Leeway:   30 * time.Second,
+//	}
+type Jwt struct {
+	Keys []Key
+	// The id of the key to issue tokens with. Default the first key
+	SigningKey string
+	// Set as iss claim by Issue and required by Verify if not empty
+	Issuer string
+	// Set as aud claim by Issue and required by Verify if not empty
+	Audience string
+	// The lifetime of issued tokens. Tokens don't expire if zero
+	Lifetime time.Duration
+	// The allowed clock skew for exp, nbf and iat
+	Leeway time.Duration
+}
+
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+	KeyId     string `json:"kid,omitempty"`
+}
+
+// Issue signs the claims with the signing key. The iat, exp, iss and aud
+// claims are added if not given.
+func (j Jwt) Issue(claims Claims) (string, error) {
+	key, err := j.signingKey()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	payload := Claims{"iat": now.Unix()}
+	if j.Lifetime > 0 {
+		payload["exp"] = now.Add(j.Lifetime).Unix()
+	}
+	if j.Issuer != "" {
+		payload["iss"] = j.Issuer
+	}
+	if j.Audience != "" {
+		payload["aud"] = j.Audience
+	}
+	for name, value := range claims {
+		if moment, ok := value.(time.Time); ok {
+			value = moment.Unix()
+		}
+		payload[name] = value
+	}
+
+	rawHeader, err := json.Marshal(header{Algorithm: key.Algorithm, Type: "JWT", KeyId: key.Id})
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	rawPayload, err := json.Marshal(payload)
+	if err != nil {
+		return "", errors.Wrap(err, "can't encode JWT claims")
+	}
+
+	input := encode(rawHeader) + "." + encode(rawPayload)
+	signature, err := key.sign([]byte(input))
+	if err != nil {
+		return "", err
+	}
+
+	return input + "." + encode(signature), nil
+}
+
+// Verify checks the signature and the claims of the token. The key is
+// selected by the kid header and must match the algorithm of the header.
+func (j Jwt) Verify(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.WithStack(MalformedTokenError)
+	}
+
+	tokenHeader := header{}
+	if err := decodeJson(parts[0], &tokenHeader); err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.WithStack(MalformedTokenError)
+	}
+
+	if !j.verifySignature(tokenHeader, []byte(parts[0]+"."+parts[1]), signature) {
+		return nil, errors.WithStack(InvalidSignatureError)
+	}
+
+	claims := Claims{}
+	if err := decodeJson(parts[1], &claims); err != nil {
+		return nil, err
+	}
+
+	return claims, j.validate(claims)
+}
+
+func (j Jwt) verifySignature(tokenHeader header, input []byte, signature []byte) bool {
+	for _, key := range j.Keys {
+		// Don't let the token choose the algorithm of the key
+		if key.Algorithm != tokenHeader.Algorithm {
+			continue
+		}
+		if tokenHeader.KeyId != "" && key.Id != tokenHeader.KeyId {
+			continue
+		}
+		if key.verify(input, signature) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (j Jwt) validate(claims Claims) error {
+	now := time.Now()
+	if expiresAt, ok := claims.ExpiresAt(); ok && !now.Before(expiresAt.Add(j.Leeway)) {
+		return errors.WithStack(ExpiredTokenError)
+	}
+	if notBefore, ok := claims.NotBefore(); ok && now.Add(j.Leeway).Before(notBefore) {
+		return errors.WithStack(TokenNotValidYetError)
+	}
+	if issuedAt, ok := claims.IssuedAt(); ok && now.Add(j.Leeway).Before(issuedAt) {
+		return errors.WithStack(TokenNotValidYetError.Wrap("token is issued in the future"))
+	}
+	if j.Issuer != "" && claims.Issuer() != j.Issuer {
+		return errors.WithStack(InvalidIssuerError)
+	}
+	if j.Audience != "" && !contains(claims.Audience(), j.Audience) {
+		return errors.WithStack(InvalidAudienceError)
+	}
+
+	return nil
+}
+
+func (j Jwt) signingKey() (Key, error) {
+	if len(j.Keys) == 0 {
+		return Key{}, errors.WithStack(InvalidKeyError.Wrap("no keys configured"))
+	}
+	if j.SigningKey == "" {
+		return j.Keys[0], nil
+	}
+	for _, key := range j.Keys {
+		if key.Id == j.SigningKey {
+			return key, nil
+		}
+	}
+
+	return Key{}, errors.WithStack(InvalidKeyError.Wrap("signing key '%s' not found", j.SigningKey))
+}
+
+func encode(raw []byte) string {
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeJson(part string, target interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return errors.WithStack(MalformedTokenError)
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		return errors.WithStack(MalformedTokenError)
+	}
+
+	return nil
+}
+
+func contains(items []string, needle string) bool {
+	for _, item := range items {
+		if item == needle {
+			return true
+		}
+	}
+	return false
+}
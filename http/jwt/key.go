@@ -0,0 +1,247 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/confetti-framework/errors"
+	"io/ioutil"
+	"math/big"
+)
+
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+)
+
+// Key signs and verifies tokens. The Id is used as kid header, so keys can be
+// rotated: new tokens are signed with the signing key, while tokens of the
+// previous keys can still be verified.
+type Key struct {
+	Id string
+	// HS256, RS256 or EdDSA
+	Algorithm string
+	// The secret of HS256
+	Secret []byte
+	// The key to sign tokens: *rsa.PrivateKey or ed25519.PrivateKey
+	PrivateKey crypto.Signer
+	// The key to verify tokens: *rsa.PublicKey or ed25519.PublicKey. It is
+	// derived from the PrivateKey if empty.
+	PublicKey crypto.PublicKey
+}
+
+func (k Key) sign(input []byte) ([]byte, error) {
+	switch k.Algorithm {
+	case HS256:
+		if len(k.Secret) == 0 {
+			return nil, errors.WithStack(InvalidKeyError.Wrap("key '%s' has no secret", k.Id))
+		}
+		mac := hmac.New(sha256.New, k.Secret)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+	case RS256:
+		private, ok := k.PrivateKey.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.WithStack(InvalidKeyError.Wrap("key '%s' has no RSA private key", k.Id))
+		}
+		hash := sha256.Sum256(input)
+		return rsa.SignPKCS1v15(rand.Reader, private, crypto.SHA256, hash[:])
+	case EdDSA:
+		private, ok := k.PrivateKey.(ed25519.PrivateKey)
+		if !ok {
+			return nil, errors.WithStack(InvalidKeyError.Wrap("key '%s' has no Ed25519 private key", k.Id))
+		}
+		return ed25519.Sign(private, input), nil
+	}
+
+	return nil, errors.WithStack(InvalidKeyError.Wrap("algorithm '%s' is not supported", k.Algorithm))
+}
+
+func (k Key) verify(input []byte, signature []byte) bool {
+	switch k.Algorithm {
+	case HS256:
+		expected, err := k.sign(input)
+		return err == nil && hmac.Equal(expected, signature)
+	case RS256:
+		public, ok := k.publicKey().(*rsa.PublicKey)
+		if !ok {
+			return false
+		}
+		hash := sha256.Sum256(input)
+		return rsa.VerifyPKCS1v15(public, crypto.SHA256, hash[:], signature) == nil
+	case EdDSA:
+		public, ok := k.publicKey().(ed25519.PublicKey)
+		return ok && ed25519.Verify(public, input, signature)
+	}
+
+	return false
+}
+
+func (k Key) publicKey() crypto.PublicKey {
+	if k.PublicKey == nil && k.PrivateKey != nil {
+		return k.PrivateKey.Public()
+	}
+	return k.PublicKey
+}
+
+// LoadPem loads a private or public RSA or Ed25519 key from a PEM file
+func LoadPem(id string, path string) (Key, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Key{}, errors.Wrap(err, "can't read JWT key")
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return Key{}, errors.WithStack(InvalidKeyError.Wrap("no PEM data found in %s", path))
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return Key{}, errors.WithStack(InvalidKeyError.Wrap("PEM type '%s' is not supported", block.Type))
+	}
+	if err != nil {
+		return Key{}, errors.WithStack(InvalidKeyError.Wrap("%s", err))
+	}
+
+	return keyByCryptoKey(id, parsed)
+}
+
+func keyByCryptoKey(id string, value interface{}) (Key, error) {
+	switch value := value.(type) {
+	case *rsa.PrivateKey:
+		return Key{Id: id, Algorithm: RS256, PrivateKey: value}, nil
+	case *rsa.PublicKey:
+		return Key{Id: id, Algorithm: RS256, PublicKey: value}, nil
+	case ed25519.PrivateKey:
+		return Key{Id: id, Algorithm: EdDSA, PrivateKey: value}, nil
+	case ed25519.PublicKey:
+		return Key{Id: id, Algorithm: EdDSA, PublicKey: value}, nil
+	}
+
+	return Key{}, errors.WithStack(InvalidKeyError.Wrap("key type %T is not supported", value))
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// OKP
+	X string `json:"x"`
+	D string `json:"d"`
+	// oct
+	K string `json:"k"`
+}
+
+// LoadJwks loads the keys of a JSON Web Key Set (RFC 7517) file. Supported are
+// public RSA keys, Ed25519 keys and symmetric keys. Keys for encryption or
+// with an unsupported key type, curve or algorithm (e.g. EC or RS512) are
+// skipped.
+func LoadJwks(path string) ([]Key, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't read JWKS")
+	}
+
+	return ParseJwks(raw)
+}
+
+// ParseJwks parses the supported keys of a JSON Web Key Set
+func ParseJwks(raw []byte) ([]Key, error) {
+	set := jwks{}
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return nil, errors.WithStack(InvalidKeyError.Wrap("invalid JWKS: %s", err))
+	}
+
+	var result []Key
+	for _, item := range set.Keys {
+		if item.Use == "enc" || !item.supported() {
+			continue
+		}
+		key, err := item.key()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, key)
+	}
+
+	return result, nil
+}
+
+// The algorithm that is supported per key type (e.g. not RS512)
+var jwkAlgorithms = map[string]string{"RSA": RS256, "OKP": EdDSA, "oct": HS256}
+
+func (j jwk) supported() bool {
+	algorithm, ok := jwkAlgorithms[j.Kty]
+	if !ok || (j.Kty == "OKP" && j.Crv != "Ed25519") {
+		return false
+	}
+
+	return j.Alg == "" || j.Alg == algorithm
+}
+
+func (j jwk) key() (Key, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(j.N)
+		if err != nil {
+			return Key{}, errors.WithStack(InvalidKeyError.Wrap("invalid modulus of key '%s'", j.Kid))
+		}
+		e, err := base64.RawURLEncoding.DecodeString(j.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return Key{}, errors.WithStack(InvalidKeyError.Wrap("invalid exponent of key '%s'", j.Kid))
+		}
+		public := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		return Key{Id: j.Kid, Algorithm: RS256, PublicKey: public}, nil
+	case "OKP":
+		if j.Crv != "Ed25519" {
+			return Key{}, errors.WithStack(InvalidKeyError.Wrap("curve '%s' of key '%s' is not supported", j.Crv, j.Kid))
+		}
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return Key{}, errors.WithStack(InvalidKeyError.Wrap("invalid public key of key '%s'", j.Kid))
+		}
+		key := Key{Id: j.Kid, Algorithm: EdDSA, PublicKey: ed25519.PublicKey(x)}
+		if j.D != "" {
+			d, err := base64.RawURLEncoding.DecodeString(j.D)
+			if err != nil || len(d) != ed25519.SeedSize {
+				return Key{}, errors.WithStack(InvalidKeyError.Wrap("invalid private key of key '%s'", j.Kid))
+			}
+			key.PrivateKey = ed25519.NewKeyFromSeed(d)
+		}
+		return key, nil
+	case "oct":
+		k, err := base64.RawURLEncoding.DecodeString(j.K)
+		if err != nil || len(k) == 0 {
+			return Key{}, errors.WithStack(InvalidKeyError.Wrap("invalid secret of key '%s'", j.Kid))
+		}
+		return Key{Id: j.Kid, Algorithm: HS256, Secret: k}, nil
+	}
+
+	return Key{}, errors.WithStack(InvalidKeyError.Wrap("key type '%s' of key '%s' is not supported", j.Kty, j.Kid))
+}
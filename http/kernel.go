@@ -4,6 +4,7 @@ import (
 	"github.com/confetti-framework/contract/inter"
 	"github.com/confetti-framework/foundation/http/http_helper"
 	"github.com/confetti-framework/foundation/http/middleware"
+	"github.com/confetti-framework/foundation/http/outcome"
 )
 
 type Kernel struct {
@@ -18,6 +19,11 @@ func (k Kernel) Handle(request inter.Request) inter.Response {
 // Send the given request through the middleware / router.
 func (k Kernel) sendRequestThroughRouter(request inter.Request) inter.Response {
 	request.App().Bind("request", request)
+	// The URL generator is created once per request, on first use
+	app := request.App()
+	app.Singleton("url", func() *outcome.UrlGenerator {
+		return outcome.NewUrlGenerator(app)
+	})
 
 	return NewRouter(request.App()).DispatchToRoute(request)
 }
@@ -0,0 +1,131 @@
+package maintenance
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPath is the marker file that puts the application in maintenance mode.
+// Bind "maintenance_path" to use another file.
+const DefaultPath = "storage/framework/down"
+
+// Mode is the content of the marker file written by app:down
+type Mode struct {
+	Time int64 `json:"time"`
+	// The seconds for the Retry-After header
+	Retry int `json:"retry,omitempty"`
+	// The path to visit to get a cookie that bypasses maintenance mode
+	Secret string `json:"secret,omitempty"`
+	// The IP addresses and CIDRs that bypass maintenance mode
+	Allow []string `json:"allow,omitempty"`
+	// Default 503
+	Status int `json:"status,omitempty"`
+	// The template to render instead of the error response
+	Template string `json:"template,omitempty"`
+}
+
+// Path returns the marker file bound as "maintenance_path" or DefaultPath
+func Path(app inter.AppReader) string {
+	if raw, err := app.MakeE("maintenance_path"); err == nil {
+		if path, ok := raw.(string); ok && path != "" {
+			return path
+		}
+	}
+	return DefaultPath
+}
+
+// Down puts the application in maintenance mode by writing the marker file
+func Down(path string, mode Mode) error {
+	if mode.Time == 0 {
+		mode.Time = time.Now().Unix()
+	}
+	raw, err := json.MarshalIndent(mode, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "can't create directory of maintenance file")
+	}
+	if err = ioutil.WriteFile(path, raw, 0644); err != nil {
+		return errors.Wrap(err, "can't write maintenance file")
+	}
+
+	return nil
+}
+
+// Up brings the application out of maintenance mode
+func Up(path string) error {
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "can't remove maintenance file")
+	}
+	return nil
+}
+
+// Active returns the mode if the application is in maintenance mode
+func Active(path string) (Mode, bool, error) {
+	raw, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Mode{}, false, nil
+	}
+	if err != nil {
+		return Mode{}, false, errors.Wrap(err, "can't read maintenance file")
+	}
+
+	mode := Mode{}
+	if err = json.Unmarshal(raw, &mode); err != nil {
+		return Mode{}, false, errors.Wrap(err, "invalid maintenance file")
+	}
+
+	return mode, true, nil
+}
+
+// BypassCookie creates the value of the cookie that bypasses maintenance mode
+func BypassCookie(secret string, expires time.Time) string {
+	timestamp := strconv.FormatInt(expires.Unix(), 10)
+	return timestamp + "." + bypassSignature(secret, timestamp)
+}
+
+// ValidBypassCookie determines whether the cookie is created with the secret and not expired
+func ValidBypassCookie(secret string, cookie string) bool {
+	parts := strings.SplitN(cookie, ".", 2)
+	if secret == "" || len(parts) != 2 {
+		return false
+	}
+	expires, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return false
+	}
+
+	return hmac.Equal([]byte(parts[1]), []byte(bypassSignature(secret, parts[0])))
+}
+
+func bypassSignature(secret string, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("maintenance|" + timestamp))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// View renders the template of app:down --view. Retry is available in the template.
+type View struct {
+	template string
+	Retry    int
+}
+
+func NewView(mode Mode) View {
+	return View{template: mode.Template, Retry: mode.Retry}
+}
+
+func (v View) Template() string {
+	return v.template
+}
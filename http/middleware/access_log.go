@@ -0,0 +1,189 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/http/auth"
+	"github.com/confetti-framework/syslog"
+	"github.com/confetti-framework/syslog/log_level"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+type AccessLogFormat string
+
+const (
+	// AccessLogCommon logs in the Common Log Format of Apache and Nginx
+	AccessLogCommon AccessLogFormat = "common"
+	// AccessLogCombined logs in the Common Log Format with referer and user agent
+	AccessLogCombined AccessLogFormat = "combined"
+	// AccessLogJson logs every request as a JSON object
+	AccessLogJson AccessLogFormat = "json"
+)
+
+const accessLogStartedAt = "access_log_started_at"
+
+// AccessLog logs every request after the response has been sent to the client.
+// The entry is written to the loggers with the fields as syslog StructuredData
+// (with id "access"), the message is formatted by Format. Use SampleRate to log
+// only a part of the requests of high-volume routes; server errors (5xx) are
+// always logged.
+//
+// Example:
+//
+//	middleware.AccessLog{}
+//	middleware.AccessLog{Channels: []string{"access"}, Format: middleware.AccessLogJson}
+//	middleware.AccessLog{SampleRate: 0.1}
+//
+//	level := log_level.NOTICE
+//	middleware.AccessLog{Level: &level}
+type AccessLog struct {
+	// Channels to log to. Default config.Logging.Default
+	Channels []string
+	// Format of the message. Default AccessLogCombined
+	Format AccessLogFormat
+	// Level of the log entries. Default INFO
+	Level *log_level.Level
+	// SampleRate is the fraction (between 0 and 1) of the requests to log.
+	// Default 0, all requests are logged
+	SampleRate float64
+}
+
+func (a AccessLog) Handle(request inter.Request, next inter.Next) inter.Response {
+	request.App().Bind(accessLogStartedAt, time.Now())
+	return next(request)
+}
+
+func (a AccessLog) Terminate(request inter.Request, response inter.Response) {
+	if !a.sampled(response) {
+		return
+	}
+
+	entry := newAccessLogEntry(request, response)
+	level := log_level.INFO
+	if a.Level != nil {
+		level = *a.Level
+	}
+
+	request.App().Log(a.Channels...).
+		Group("access").
+		LogWith(level, entry.format(a.Format), syslog.StructuredData{"access": entry.structuredData()})
+}
+
+func (a AccessLog) sampled(response inter.Response) bool {
+	if a.SampleRate <= 0 || a.SampleRate >= 1 || response.GetStatus() >= 500 {
+		return true
+	}
+	return rand.Float64() < a.SampleRate
+}
+
+type accessLogEntry struct {
+	Time      time.Time `json:"time"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Uri       string    `json:"uri"`
+	Protocol  string    `json:"protocol"`
+	Route     string    `json:"route,omitempty"`
+	Status    int       `json:"status"`
+	Bytes     int       `json:"bytes"`
+	Duration  float64   `json:"duration_ms"`
+	Ip        string    `json:"ip"`
+	User      string    `json:"user,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	Referer   string    `json:"referer,omitempty"`
+	RequestId string    `json:"request_id,omitempty"`
+}
+
+func newAccessLogEntry(request inter.Request, response inter.Response) accessLogEntry {
+	source := request.Source()
+	entry := accessLogEntry{
+		Time:      time.Now(),
+		Method:    request.Method(),
+		Path:      request.Path(),
+		Uri:       source.URL.RequestURI(),
+		Protocol:  source.Proto,
+		Status:    response.GetStatus(),
+		Ip:        clientIp(request),
+		UserAgent: request.Header("User-Agent"),
+		Referer:   request.Header("Referer"),
+		RequestId: response.GetHeaders().Get(requestHeaderIDName),
+	}
+
+	if startedAt, err := request.App().MakeE(accessLogStartedAt); err == nil {
+		entry.Time = startedAt.(time.Time)
+		entry.Duration = float64(time.Since(entry.Time).Microseconds()) / 1000
+	}
+	if route, err := request.App().MakeE("route"); err == nil {
+		entry.Route = route.(inter.Route).Name()
+	}
+	if user, err := auth.FromApp(request.App()); err == nil {
+		entry.User = fmt.Sprint(user.AuthIdentifier())
+	}
+	if entry.RequestId == "" {
+		entry.RequestId = request.Header(requestHeaderIDName)
+	}
+	// The length of the body is set when the response is written
+	if length, err := strconv.Atoi(response.GetHeader("Content-Length")); err == nil {
+		entry.Bytes = length
+	}
+
+	return entry
+}
+
+func (e accessLogEntry) format(format AccessLogFormat) string {
+	switch format {
+	case AccessLogCommon:
+		return e.common()
+	case AccessLogJson:
+		result, err := json.Marshal(e)
+		if err != nil {
+			panic(err)
+		}
+		return string(result)
+	default:
+		return fmt.Sprintf("%s %q %q", e.common(), orDash(e.Referer), orDash(e.UserAgent))
+	}
+}
+
+// common formats the entry as: host ident authuser [date] "request" status bytes
+func (e accessLogEntry) common() string {
+	bytes := "-"
+	if e.Bytes > 0 {
+		bytes = strconv.Itoa(e.Bytes)
+	}
+
+	return fmt.Sprintf(
+		"%s - %s [%s] \"%s %s %s\" %d %s",
+		orDash(e.Ip),
+		orDash(e.User),
+		e.Time.Format("02/Jan/2006:15:04:05 -0700"),
+		e.Method,
+		e.Uri,
+		e.Protocol,
+		e.Status,
+		bytes,
+	)
+}
+
+func (e accessLogEntry) structuredData() syslog.SDElement {
+	return syslog.SDElement{
+		"method":      e.Method,
+		"path":        e.Path,
+		"route":       e.Route,
+		"status":      strconv.Itoa(e.Status),
+		"bytes":       strconv.Itoa(e.Bytes),
+		"duration_ms": strconv.FormatFloat(e.Duration, 'f', 3, 64),
+		"ip":          e.Ip,
+		"user_agent":  e.UserAgent,
+		"request_id":  e.RequestId,
+	}
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
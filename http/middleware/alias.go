@@ -0,0 +1,198 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"sort"
+	"time"
+)
+
+// Groups are lists of middlewares by name. Bind your own Groups as
+// "middleware_groups" to replace DefaultGroups.
+type Groups map[string][]inter.HttpMiddleware
+
+// Aliases are middlewares by name. Bind them as "middleware_aliases".
+type Aliases map[string]inter.HttpMiddleware
+
+// DefaultGroups are used when "middleware_groups" is not bound. The middlewares
+// resolve their stores from the container, so bind them before you use a group:
+//
+//   - "web" responds with HTML. StartSession{} stores the session in the
+//     "session_driver" or else in a cookie encrypted with config.App.Key.
+//     Requests are refused while the key is shorter than 32 characters.
+//     VerifyCsrfToken{} verifies the token of all POST, PUT, PATCH and DELETE
+//     requests, without exceptions.
+//   - "api" responds with JSON. Throttle limits 60 requests per minute per IP
+//     address in the "rate_limit_store" or else in a memory store of the
+//     process. Bind a shared store (e.g. NewSqlRateLimitStore) when you run
+//     more than one instance.
+var DefaultGroups = Groups{
+	"web": {
+		DefaultResponseOutcome{Outcome: outcome.Html},
+		StartSession{},
+		VerifyCsrfToken{},
+	},
+	"api": {
+		DefaultResponseOutcome{Outcome: outcome.Json},
+		Throttle{MaxAttempts: 60, Decay: time.Minute},
+	},
+}
+
+// DefaultPriority is the order of the middlewares that depend on each other.
+// Bind your own list as "middleware_priority" to replace it.
+var DefaultPriority = []inter.HttpMiddleware{
+	MethodOverride{},
+	StartSession{},
+	VerifyCsrfToken{},
+	Authenticate(""),
+	Throttle{},
+	SubstituteBindings{},
+	Can(""),
+}
+
+// Alias refers to a group of "middleware_groups" or a middleware of
+// "middleware_aliases". The alias is resolved when the route is dispatched.
+//
+// Example:
+//
+//	routing.Group(...).Middleware(middleware.Alias("web"), middleware.Alias("auth"))
+type Alias string
+
+// Handle resolves the alias, so an alias also works in a pipeline of its own
+func (a Alias) Handle(request inter.Request, next inter.Next) inter.Response {
+	return NewPipeline(request.App()).
+		Send(request).
+		Through(Resolve(request.App(), []inter.HttpMiddleware{a}, nil)).
+		Then(next)
+}
+
+// Name returns the name to compare middlewares by. A middleware is identified
+// by type, except an Alias, which is identified by the name of the alias.
+func Name(middleware inter.HttpMiddleware) string {
+	if alias, ok := middleware.(Alias); ok {
+		return fmt.Sprintf("%T(%s)", alias, string(alias))
+	}
+	return fmt.Sprintf("%T", middleware)
+}
+
+// Resolve expands the aliases, removes the excluded middlewares and sorts
+// the middlewares by priority.
+func Resolve(app inter.AppReader, middlewares []inter.HttpMiddleware, excluded []inter.HttpMiddleware) []inter.HttpMiddleware {
+	excludedNames := map[string]bool{}
+	for _, middleware := range excluded {
+		excludedNames[Name(middleware)] = true
+	}
+
+	resolved := expand(app, middlewares, excludedNames, map[Alias]bool{})
+
+	return SortByPriority(resolved, priority(app))
+}
+
+// SortByPriority moves the middlewares of the priority list to the order of
+// the list. The other middlewares keep their position.
+func SortByPriority(middlewares []inter.HttpMiddleware, priority []inter.HttpMiddleware) []inter.HttpMiddleware {
+	rank := map[string]int{}
+	for i, middleware := range priority {
+		if _, ok := rank[Name(middleware)]; !ok {
+			rank[Name(middleware)] = i
+		}
+	}
+
+	var positions []int
+	var prioritized []inter.HttpMiddleware
+	for i, middleware := range middlewares {
+		if _, ok := rank[Name(middleware)]; ok {
+			positions = append(positions, i)
+			prioritized = append(prioritized, middleware)
+		}
+	}
+	sort.SliceStable(prioritized, func(i, j int) bool {
+		return rank[Name(prioritized[i])] < rank[Name(prioritized[j])]
+	})
+
+	result := append([]inter.HttpMiddleware{}, middlewares...)
+	for i, position := range positions {
+		result[position] = prioritized[i]
+	}
+
+	return result
+}
+
+func expand(
+	app inter.AppReader,
+	middlewares []inter.HttpMiddleware,
+	excluded map[string]bool,
+	resolving map[Alias]bool,
+) []inter.HttpMiddleware {
+	var result []inter.HttpMiddleware
+	for _, middleware := range middlewares {
+		if excluded[Name(middleware)] {
+			continue
+		}
+		alias, ok := middleware.(Alias)
+		if !ok {
+			result = append(result, middleware)
+			continue
+		}
+
+		if resolving[alias] {
+			panic(errors.WithStack(MiddlewareNotFoundError.Wrap("alias '%s' refers to itself", alias)))
+		}
+		resolving[alias] = true
+		result = append(result, expand(app, resolveAlias(app, alias), excluded, resolving)...)
+		delete(resolving, alias)
+	}
+
+	return result
+}
+
+func resolveAlias(app inter.AppReader, alias Alias) []inter.HttpMiddleware {
+	if group, ok := groups(app)[string(alias)]; ok {
+		return group
+	}
+	if raw, err := app.MakeE("middleware_aliases"); err == nil {
+		var middleware inter.HttpMiddleware
+		var ok bool
+		switch aliases := raw.(type) {
+		case Aliases:
+			middleware, ok = aliases[string(alias)]
+		case map[string]inter.HttpMiddleware:
+			middleware, ok = aliases[string(alias)]
+		}
+		if ok {
+			return []inter.HttpMiddleware{middleware}
+		}
+	}
+
+	panic(errors.WithStack(MiddlewareNotFoundError.Wrap("no group or alias '%s' found", alias)))
+}
+
+func groups(app inter.AppReader) Groups {
+	raw, err := app.MakeE("middleware_groups")
+	if err != nil {
+		return DefaultGroups
+	}
+
+	switch result := raw.(type) {
+	case Groups:
+		return result
+	case map[string][]inter.HttpMiddleware:
+		return result
+	}
+
+	return DefaultGroups
+}
+
+func priority(app inter.AppReader) []inter.HttpMiddleware {
+	raw, err := app.MakeE("middleware_priority")
+	if err != nil {
+		return DefaultPriority
+	}
+	if result, ok := raw.([]inter.HttpMiddleware); ok {
+		return result
+	}
+
+	return DefaultPriority
+}
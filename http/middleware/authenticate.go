@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation/http/auth"
+	net "net/http"
+)
+
+// Authenticate rejects the request with status 401 if the guard can't
+// determine the user. The guard is resolved by name from the auth.Guards in
+// "auth_guards". The user is available with request.User().
+//
+// Example:
+//
+//	routing.Get("/api/orders", controllers.Orders).Middleware(middleware.Authenticate("api"))
+type Authenticate string
+
+func (a Authenticate) Handle(request inter.Request, next inter.Next) inter.Response {
+	guard, err := auth.GuardByName(request.App(), string(a))
+	if err != nil {
+		return errorResponse(request, err)
+	}
+
+	user, err := guard.User(request)
+	if err != nil {
+		response := errorResponse(request, err)
+		challenger, ok := guard.(auth.Challenger)
+		// The client is only asked to authenticate if it isn't authenticated
+		if status, _ := errors.FindStatus(err); ok && status == net.StatusUnauthorized {
+			response.GetHeaders().Set("WWW-Authenticate", challenger.Challenge())
+		}
+		return response
+	}
+	request.App().Bind("user", user)
+
+	return next(request)
+}
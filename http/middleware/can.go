@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/http/auth"
+)
+
+// Can rejects the request with status 403 if the authenticated user may not
+// perform the ability of the auth.Gate. Add the middleware after
+// middleware.Authenticate.
+//
+// Example:
+//
+//	routing.Get("/dashboard", controllers.Dashboard).Middleware(middleware.Authenticate("web"), middleware.Can("view-dashboard"))
+type Can string
+
+func (c Can) Handle(request inter.Request, next inter.Next) inter.Response {
+	user, err := auth.FromApp(request.App())
+	if err != nil {
+		return errorResponse(request, err)
+	}
+
+	if err := auth.GateFromApp(request.App()).Authorize(user, string(c)); err != nil {
+		return errorResponse(request, err)
+	}
+
+	return next(request)
+}
@@ -32,3 +32,12 @@ func getDefaultResponseEncoder(request inter.Request) func(interface{}) inter.Re
 	}
 	return instance.(func(interface{}) inter.Response)
 }
+
+// Convert an error to a response with the status of the error
+func errorResponse(request inter.Request, err error) inter.Response {
+	response := getDefaultResponseEncoder(request)(err)
+	if status, ok := errors.FindStatus(err); ok {
+		response.Status(status)
+	}
+	return response
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation/decorator/response_decorator"
+	"github.com/confetti-framework/foundation/http/http_helper"
+	"github.com/confetti-framework/foundation/http/maintenance"
+	"github.com/confetti-framework/foundation/http/outcome"
+	net "net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maintenanceCookie = "confetti_maintenance"
+
+// CheckForMaintenanceMode returns status 503 while the application is put
+// down with app:down. It is run by the framework before the global middlewares
+// and before the route is matched, so no middleware runs while the application
+// is down. The response therefore uses "default_response_outcome" of the
+// application instead of the outcome of the route.
+type CheckForMaintenanceMode struct{}
+
+func (c CheckForMaintenanceMode) Handle(request inter.Request, next inter.Next) inter.Response {
+	mode, active, err := maintenance.Active(maintenance.Path(request.App()))
+	if err != nil {
+		panic(err)
+	}
+	if !active {
+		return next(request)
+	}
+
+	if mode.Secret != "" && strings.Trim(request.Path(), "/") == mode.Secret {
+		return decorate(request, bypassResponse(request, mode))
+	}
+	if cookie, _ := request.CookieE(maintenanceCookie); maintenance.ValidBypassCookie(mode.Secret, cookie) {
+		return next(request)
+	}
+	if len(mode.Allow) > 0 && http_helper.IsTrustedProxy(clientIp(request), http_helper.TrustedProxies(mode.Allow)) {
+		return next(request)
+	}
+
+	return decorate(request, maintenanceResponse(request, mode))
+}
+
+// decorate applies the "response_decorators", because the response doesn't
+// pass DecorateResponse of the route
+func decorate(request inter.Request, response inter.Response) inter.Response {
+	decorators, err := request.App().MakeE("response_decorators")
+	if err != nil {
+		return response
+	}
+
+	return response_decorator.Handler{Decorators: decorators.([]inter.ResponseDecorator)}.Decorate(response)
+}
+
+// bypassResponse redirects to the homepage with a cookie that bypasses maintenance mode
+func bypassResponse(request inter.Request, mode maintenance.Mode) inter.Response {
+	expires := time.Now().Add(12 * time.Hour)
+	response := outcome.RedirectTemporary("/")
+	response.Cookie(net.Cookie{
+		Name:     maintenanceCookie,
+		Value:    maintenance.BypassCookie(mode.Secret, expires),
+		Path:     "/",
+		Expires:  expires,
+		Secure:   isSecure(request),
+		HttpOnly: true,
+		SameSite: net.SameSiteLaxMode,
+	})
+
+	return response
+}
+
+func maintenanceResponse(request inter.Request, mode maintenance.Mode) inter.Response {
+	status := mode.Status
+	if status == 0 {
+		status = net.StatusServiceUnavailable
+	}
+
+	var response inter.Response
+	if mode.Template != "" {
+		response = outcome.Html(maintenance.NewView(mode)).Status(status)
+	} else {
+		response = errorResponse(request, errors.WithStack(MaintenanceModeError.Status(status)))
+	}
+	if mode.Retry > 0 {
+		response.GetHeaders().Set("Retry-After", strconv.Itoa(mode.Retry))
+	}
+
+	return response
+}
+
+func clientIp(request inter.Request) string {
+	if source, ok := request.(interface{ Ip() string }); ok {
+		return source.Ip()
+	}
+	return http_helper.StripPort(request.Source().RemoteAddr)
+}
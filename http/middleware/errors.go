@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/syslog/log_level"
+	net "net/http"
+)
+
+var InvalidSignatureError = errors.New("invalid signature").Status(net.StatusForbidden).Level(log_level.DEBUG)
+var ExpiredSignatureError = InvalidSignatureError.Wrap("signature has expired")
+var TooManyRequestsError = errors.New("too many requests").Status(net.StatusTooManyRequests).Level(log_level.DEBUG)
+var RateLimitStoreError = errors.New("rate limit store unavailable").Status(net.StatusServiceUnavailable).Level(log_level.ERROR)
+var TimeoutError = errors.New("request timeout").Status(net.StatusServiceUnavailable).Level(log_level.WARNING)
+var CsrfTokenMismatchError = errors.New("CSRF token mismatch").Status(419).Level(log_level.DEBUG)
+var MaintenanceModeError = errors.New("service unavailable due to maintenance").Status(net.StatusServiceUnavailable).Level(log_level.INFO)
+var MiddlewareNotFoundError = errors.New("middleware not found").Status(net.StatusInternalServerError).Level(log_level.ERROR)
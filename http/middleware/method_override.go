@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/http/method"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	net "net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	methodOverrideField  = "_method"
+	methodOverrideHeader = "X-HTTP-Method-Override"
+)
+
+// The methods a POST request can be changed to
+var overridableMethods = []string{method.Put, method.Patch, method.Delete}
+
+// MaxFormSize is the maximum number of bytes of a form body that is read
+// before the route is matched to find the _method or _token field
+var MaxFormSize int64 = 10 << 20
+
+// MethodOverride changes the method of a POST request to the method of the
+// X-HTTP-Method-Override header or the _method form field, so HTML forms can
+// reach PUT, PATCH and DELETE routes. The route is matched by the new method,
+// so add this middleware to the global middlewares:
+//
+//	app.Bind("global_middlewares", []inter.HttpMiddleware{middleware.MethodOverride{}})
+type MethodOverride struct{}
+
+func (m MethodOverride) Handle(request inter.Request, next inter.Next) inter.Response {
+	if request.Method() != method.Post {
+		return next(request)
+	}
+
+	override := strings.ToUpper(request.Header(methodOverrideHeader))
+	if override == "" {
+		override = strings.ToUpper(formValue(request, methodOverrideField))
+	}
+
+	if overridable, ok := request.(interface {
+		SetMethod(method string) inter.Request
+	}); ok && inMethods(override, overridableMethods) {
+		overridable.SetMethod(override)
+	}
+
+	return next(request)
+}
+
+// formValue reads the first field with the key from a form body. The fields
+// are read in order until the key is found, so only the part of the body
+// before the field is kept in memory and a large upload can follow the field.
+// The field has to end within the first MaxFormSize bytes. The body is
+// restored, so the form can be decoded again after the route is matched.
+func formValue(request inter.Request, key string) string {
+	mediaType, params, err := mime.ParseMediaType(request.Header("Content-Type"))
+	if err != nil || (mediaType != "application/x-www-form-urlencoded" && mediaType != "multipart/form-data") {
+		return ""
+	}
+	restorable, ok := request.(bodyReaderSetter)
+	if !ok {
+		return ""
+	}
+
+	source := request.Source()
+	if source.Body == nil || source.Body == net.NoBody {
+		return ""
+	}
+	original := source.Body
+	consumed := &bytes.Buffer{}
+	// Read one byte more than allowed to detect a larger body
+	body := io.LimitReader(io.TeeReader(original, consumed), MaxFormSize+1)
+	var value string
+	if mediaType == "multipart/form-data" {
+		value = multipartValue(body, params["boundary"], key)
+	} else {
+		value = urlEncodedValue(body, key, func() bool {
+			return int64(consumed.Len()) > MaxFormSize
+		})
+	}
+
+	// The unread part of the body follows the consumed part
+	restorable.SetBodyReader(readCloser{io.MultiReader(consumed, original), original})
+
+	return value
+}
+
+// urlEncodedValue reads the pairs until the key is found. The last pair is
+// ignored when the body is truncated by MaxFormSize.
+func urlEncodedValue(body io.Reader, key string, truncated func() bool) string {
+	reader := bufio.NewReader(body)
+	for {
+		pair, err := reader.ReadString('&')
+		if err != nil && (err != io.EOF || truncated()) {
+			return ""
+		}
+
+		name, value := strings.TrimSuffix(pair, "&"), ""
+		if index := strings.Index(name, "="); index >= 0 {
+			name, value = name[:index], name[index+1:]
+		}
+		if name, nameErr := url.QueryUnescape(name); nameErr == nil && name == key {
+			value, valueErr := url.QueryUnescape(value)
+			if valueErr != nil {
+				return ""
+			}
+			return value
+		}
+
+		if err == io.EOF {
+			return ""
+		}
+	}
+}
+
+// multipartValue reads the parts until the field of the key is found. A part
+// that is truncated by MaxFormSize misses its closing boundary and is ignored.
+func multipartValue(body io.Reader, boundary string, key string) string {
+	reader := multipart.NewReader(body, boundary)
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return ""
+		}
+		if part.FormName() != key || part.FileName() != "" {
+			continue
+		}
+
+		value, err := ioutil.ReadAll(part)
+		if err != nil {
+			return ""
+		}
+		return string(value)
+	}
+}
+
+type bodyReaderSetter interface {
+	SetBodyReader(body io.ReadCloser) inter.Request
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
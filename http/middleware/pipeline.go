@@ -3,7 +3,6 @@ package middleware
 import (
 	"github.com/confetti-framework/contract/inter"
 	"github.com/confetti-framework/support/caller"
-	"sort"
 )
 
 type Pipe interface {
@@ -49,10 +48,7 @@ func (p Pipeline) Then(controller inter.Controller) inter.Response {
 	var holder inter.PipeHolder
 	var holders []inter.PipeHolder
 	nextHolder := 0
-	pipes := p.Pipes
-
-	stabilizeOrder(holders)
-	pipes = reverse(pipes)
+	pipes := reverse(p.Pipes)
 
 	for i, pipe := range pipes {
 		// Clone pipe and disconnect the reference.
@@ -85,13 +81,6 @@ func (p Pipeline) Then(controller inter.Controller) inter.Response {
 	return holders[nextHolder](p.Passable)
 }
 
-// Ensure the pipe holders are stable sorted.
-func stabilizeOrder(holders []inter.PipeHolder) {
-	sort.SliceStable(holders, func(i, j int) bool {
-		return true
-	})
-}
-
 func setDefaultHolder(controller inter.Controller, pipeHolders []inter.PipeHolder) []inter.PipeHolder {
 	// If no pipe holders can be generated because no pipes
 	// are present, proceed directly to the destination
@@ -106,10 +95,12 @@ func getNextIndex(pipeHolders []inter.PipeHolder) int {
 	return len(pipeHolders) - 1
 }
 
+// reverse returns a reversed copy, so the pipes of the caller keep their order
 func reverse(pipes []inter.HttpMiddleware) []inter.HttpMiddleware {
-	for left, right := 0, len(pipes)-1; left < right; left, right = left+1, right-1 {
-		pipes[left], pipes[right] = pipes[right], pipes[left]
+	result := make([]inter.HttpMiddleware, len(pipes))
+	for i, pipe := range pipes {
+		result[len(pipes)-1-i] = pipe
 	}
 
-	return pipes
+	return result
 }
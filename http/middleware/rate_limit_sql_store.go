@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation/db"
+	"time"
+)
+
+// SqlRateLimitStore keeps the token buckets in a database table, so multiple
+// servers share the same limits. Use one of the connections of the
+// DatabaseServiceProvider and create the table first:
+//
+//	CREATE TABLE rate_limits (
+//		bucket VARCHAR(255) NOT NULL PRIMARY KEY,
+//		tokens DOUBLE PRECISION NOT NULL,
+//		updated_at BIGINT NOT NULL
+//	)
+//
+// Bind the store to use it for all throttled routes:
+//
+//	app.Bind("rate_limit_store", middleware.NewSqlRateLimitStore(connection))
+type SqlRateLimitStore struct {
+	Connection inter.Connection
+	// Default rate_limits
+	Table string
+}
+
+func NewSqlRateLimitStore(connection inter.Connection) SqlRateLimitStore {
+	return SqlRateLimitStore{Connection: connection, Table: "rate_limits"}
+}
+
+func (s SqlRateLimitStore) Take(key string, maxAttempts int, decay time.Duration) (RateLimit, error) {
+	limit, err := s.take(key, maxAttempts, decay)
+	if err != nil {
+		// A concurrent request may have inserted the same bucket
+		limit, err = s.take(key, maxAttempts, decay)
+	}
+
+	return limit, err
+}
+
+func (s SqlRateLimitStore) take(key string, maxAttempts int, decay time.Duration) (RateLimit, error) {
+	ctx, cancel := db.Context(s.Connection)
+	defer cancel()
+
+	tx, err := s.Connection.Pool().BeginTx(ctx, nil)
+	if err != nil {
+		return RateLimit{}, errors.Wrap(err, "can't start rate limit transaction")
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now()
+	bucket := tokenBucket{Tokens: float64(maxAttempts), Updated: now}
+	var updatedAt int64
+	err = tx.QueryRowContext(
+		ctx,
+		s.query("SELECT tokens, updated_at FROM %s WHERE bucket = ? FOR UPDATE"),
+		key,
+	).Scan(&bucket.Tokens, &updatedAt)
+	exists := err == nil
+	if err != nil && err != sql.ErrNoRows {
+		return RateLimit{}, errors.Wrap(err, "can't read rate limit")
+	}
+	if exists {
+		bucket.Updated = time.Unix(0, updatedAt)
+	}
+
+	limit := bucket.take(now, maxAttempts, decay)
+
+	if exists {
+		_, err = tx.ExecContext(
+			ctx,
+			s.query("UPDATE %s SET tokens = ?, updated_at = ? WHERE bucket = ?"),
+			bucket.Tokens, bucket.Updated.UnixNano(), key,
+		)
+	} else {
+		_, err = tx.ExecContext(
+			ctx,
+			s.query("INSERT INTO %s (bucket, tokens, updated_at) VALUES (?, ?, ?)"),
+			key, bucket.Tokens, bucket.Updated.UnixNano(),
+		)
+	}
+	if err != nil {
+		return RateLimit{}, errors.Wrap(err, "can't save rate limit")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return RateLimit{}, errors.Wrap(err, "can't save rate limit")
+	}
+
+	return limit, nil
+}
+
+// query adds the table name and converts the placeholders to the syntax of the driver
+func (s SqlRateLimitStore) query(query string) string {
+	table := s.Table
+	if table == "" {
+		table = "rate_limits"
+	}
+	query = fmt.Sprintf(query, table)
+
+	return db.Placeholders(s.Connection, query)
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimitStore keeps the token buckets of the Throttle middleware. Implement
+// this interface to share the limits between multiple servers.
+type RateLimitStore interface {
+	// Take removes a token from the bucket of the key if one is available
+	Take(key string, maxAttempts int, decay time.Duration) (RateLimit, error)
+}
+
+type RateLimit struct {
+	Allowed   bool
+	Remaining int
+	// The duration until a new token is available
+	RetryAfter time.Duration
+}
+
+var defaultRateLimitStore = NewMemoryRateLimitStore()
+
+// MemoryRateLimitStore keeps the buckets in memory of the current process
+type MemoryRateLimitStore struct {
+	lock    sync.Mutex
+	buckets map[string]*tokenBucket
+	takes   int
+}
+
+type tokenBucket struct {
+	Tokens  float64
+	Updated time.Time
+	decay   time.Duration
+}
+
+// The number of takes after which the full buckets are removed
+const memoryStorePruneInterval = 1000
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: map[string]*tokenBucket{}}
+}
+
+func (m *MemoryRateLimitStore) Take(key string, maxAttempts int, decay time.Duration) (RateLimit, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := time.Now()
+	m.takes++
+	if m.takes%memoryStorePruneInterval == 0 {
+		m.prune(now)
+	}
+
+	bucket, ok := m.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{Tokens: float64(maxAttempts), Updated: now}
+		m.buckets[key] = bucket
+	}
+
+	return bucket.take(now, maxAttempts, decay), nil
+}
+
+// prune removes the buckets that have been refilled completely
+func (m *MemoryRateLimitStore) prune(now time.Time) {
+	for key, bucket := range m.buckets {
+		if now.Sub(bucket.Updated) >= bucket.decay {
+			delete(m.buckets, key)
+		}
+	}
+}
+
+// take refills the bucket by the elapsed time and removes a token if available
+func (b *tokenBucket) take(now time.Time, maxAttempts int, decay time.Duration) RateLimit {
+	if maxAttempts <= 0 {
+		return RateLimit{RetryAfter: decay}
+	}
+
+	perToken := decay / time.Duration(maxAttempts)
+	if perToken > 0 {
+		b.Tokens += float64(now.Sub(b.Updated)) / float64(perToken)
+	} else {
+		b.Tokens = float64(maxAttempts)
+	}
+	b.Tokens = math.Min(b.Tokens, float64(maxAttempts))
+	b.Updated = now
+	b.decay = decay
+
+	if b.Tokens < 1 {
+		return RateLimit{RetryAfter: time.Duration((1 - b.Tokens) * float64(perToken))}
+	}
+
+	b.Tokens--
+	return RateLimit{Allowed: true, Remaining: int(b.Tokens)}
+}
@@ -3,21 +3,81 @@ package middleware
 import (
 	"github.com/confetti-framework/contract/inter"
 	"github.com/confetti-framework/foundation/encoder"
-	"github.com/confetti-framework/foundation/http/http_helper"
+	"github.com/confetti-framework/support"
+	"mime"
+	"strings"
 )
 
+// BodyDecoders maps a media type to a decoder of the request body. Bind your
+// own BodyDecoders as "request_body_decoders" to support other formats.
+type BodyDecoders map[string]func(request inter.Request) support.Value
+
+var DefaultBodyDecoders = BodyDecoders{
+	"application/json":                  encoder.RequestWithJsonToValue,
+	"text/json":                         encoder.RequestWithJsonToValue,
+	"multipart/form-data":               encoder.RequestWithFormToValue,
+	"application/x-www-form-urlencoded": encoder.RequestWithFormToValue,
+	"application/xml":                   encoder.RequestWithXmlToValue,
+	"text/xml":                          encoder.RequestWithXmlToValue,
+	"application/yaml":                  encoder.RequestWithYamlToValue,
+	"application/x-yaml":                encoder.RequestWithYamlToValue,
+	"text/yaml":                         encoder.RequestWithYamlToValue,
+	"application/msgpack":               encoder.RequestWithMsgpackToValue,
+	"application/x-msgpack":             encoder.RequestWithMsgpackToValue,
+	"application/vnd.msgpack":           encoder.RequestWithMsgpackToValue,
+	"application/x-ndjson":              encoder.RequestWithNdjsonToValue,
+	"text/plain":                        encoder.RequestWithTextToValue,
+}
+
 type RequestBodyDecoder struct{}
 
-// This ensures the request can be decoded from JSON and Multipart Form.
-// Feel free to add your own decoders to support other formats as well.
+// This ensures the request can be decoded by the media type of the request.
+// The decoders are resolved from "request_body_decoders" in the container
+// and fall back to DefaultBodyDecoders.
 func (r RequestBodyDecoder) Handle(request inter.Request, next inter.Next) inter.Response {
-
-	switch {
-	case http_helper.HasJson(request):
-		request.App().Bind(inter.RequestBodyDecoder, encoder.RequestWithJsonToValue)
-	case http_helper.HasMultiPartFormData(request):
-		request.App().Bind(inter.RequestBodyDecoder, encoder.RequestWithFormToValue)
+	decoder, ok := decoderByMediaType(bodyDecoders(request), request.Header("Content-Type"))
+	if ok {
+		request.App().Bind(inter.RequestBodyDecoder, decoder)
 	}
 
 	return next(request)
 }
+
+func bodyDecoders(request inter.Request) BodyDecoders {
+	raw, err := request.App().MakeE("request_body_decoders")
+	if err != nil {
+		return DefaultBodyDecoders
+	}
+
+	switch decoders := raw.(type) {
+	case BodyDecoders:
+		return decoders
+	case map[string]func(request inter.Request) support.Value:
+		return decoders
+	}
+
+	return DefaultBodyDecoders
+}
+
+// A structured syntax suffix (e.g. application/vnd.api+json) falls back
+// to the decoder of the suffix (e.g. application/json).
+func decoderByMediaType(
+	decoders BodyDecoders,
+	contentType string,
+) (func(request inter.Request) support.Value, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+
+	if decoder, ok := decoders[mediaType]; ok {
+		return decoder, true
+	}
+
+	if i := strings.LastIndex(mediaType, "+"); i != -1 {
+		decoder, ok := decoders["application/"+mediaType[i+1:]]
+		return decoder, ok
+	}
+
+	return nil, false
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/http/view_helper"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DisableHeader can be used as value to omit a header of SecureHeaders
+const DisableHeader = "-"
+
+const cspNoncePlaceholder = "{nonce}"
+
+// SecureHeaders adds the security headers to the response. Empty fields use
+// the defaults. A header that is already set by the controller is not
+// replaced, so a route can deviate from its group.
+//
+// The Content-Security-Policy can contain {nonce}, which is replaced by a random
+// nonce per request. The nonce is available in templates by csp_nonce:
+//
+//	middleware.SecureHeaders{ContentSecurityPolicy: "default-src 'self'; script-src 'self' 'nonce-{nonce}'"}
+//
+//	<script nonce="{{ csp_nonce }}">...</script>
+type SecureHeaders struct {
+	// The max-age of Strict-Transport-Security, which is only sent over HTTPS.
+	// Default 1 year. A negative duration omits the header.
+	HstsMaxAge            time.Duration
+	HstsIncludeSubdomains bool
+	HstsPreload           bool
+	// Default nosniff
+	ContentTypeOptions string
+	// Default SAMEORIGIN
+	FrameOptions string
+	// Default strict-origin-when-cross-origin
+	ReferrerPolicy string
+	// Omitted if empty (e.g. "camera=(), microphone=(), geolocation=()")
+	PermissionsPolicy string
+	// Omitted if empty
+	ContentSecurityPolicy string
+	// Send Content-Security-Policy-Report-Only to test the policy
+	ContentSecurityPolicyReportOnly bool
+}
+
+func (s SecureHeaders) Handle(request inter.Request, next inter.Next) inter.Response {
+	nonce := newCspNonce()
+	request.App().Bind("csp_nonce", nonce)
+	view_helper.AddTemplateFunctions(request.App(), template.FuncMap{
+		"csp_nonce": func() string {
+			return nonce
+		},
+	})
+
+	response := next(request)
+
+	if isSecure(request) && s.HstsMaxAge >= 0 {
+		setHeaderIfMissing(response, "Strict-Transport-Security", s.hsts())
+	}
+	setHeaderIfMissing(response, "X-Content-Type-Options", valueOr(s.ContentTypeOptions, "nosniff"))
+	setHeaderIfMissing(response, "X-Frame-Options", valueOr(s.FrameOptions, "SAMEORIGIN"))
+	setHeaderIfMissing(response, "Referrer-Policy", valueOr(s.ReferrerPolicy, "strict-origin-when-cross-origin"))
+	setHeaderIfMissing(response, "Permissions-Policy", s.PermissionsPolicy)
+
+	cspHeader := "Content-Security-Policy"
+	if s.ContentSecurityPolicyReportOnly {
+		cspHeader = "Content-Security-Policy-Report-Only"
+	}
+	setHeaderIfMissing(response, cspHeader, strings.ReplaceAll(s.ContentSecurityPolicy, cspNoncePlaceholder, nonce))
+
+	return response
+}
+
+func (s SecureHeaders) hsts() string {
+	maxAge := s.HstsMaxAge
+	if maxAge == 0 {
+		maxAge = 365 * 24 * time.Hour
+	}
+
+	result := "max-age=" + strconv.Itoa(int(maxAge.Seconds()))
+	if s.HstsIncludeSubdomains {
+		result += "; includeSubDomains"
+	}
+	if s.HstsPreload {
+		result += "; preload"
+	}
+
+	return result
+}
+
+func setHeaderIfMissing(response inter.Response, key string, value string) {
+	if value == "" || value == DisableHeader || response.GetHeader(key) != "" {
+		return
+	}
+	response.GetHeaders().Set(key, value)
+}
+
+func valueOr(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func newCspNonce() string {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/http/http_helper"
+	"github.com/confetti-framework/foundation/http/session"
+	"math/rand"
+	net "net/http"
+	"time"
+)
+
+// StartSession loads the session of the client and binds it as "session", so
+// it can be used with request.Session(). After the request, the session is
+// saved and the cookie is added to the response. Expired sessions are removed
+// after the response has been sent (see Terminate). The driver is resolved from
+// "session_driver" in the container and falls back to a CookieDriver that
+// encrypts the data with config.App.Key. Without a driver, requests are
+// refused while config.App.Key is empty or shorter than 32 characters.
+//
+// Example:
+//
+//	middleware.StartSession{Driver: session.FileDriver{Path: "storage/framework/sessions"}}
+//	middleware.StartSession{Driver: session.NewDatabaseDriver(connection), Lifetime: 24 * time.Hour}
+type StartSession struct {
+	Driver session.Driver
+	// Default 2 hours
+	Lifetime time.Duration
+	// The name of the cookie. Default confetti_session
+	Cookie string
+	// The chance to remove expired sessions per request. Default 2 in 100
+	Lottery [2]int
+	// Only send the cookie over HTTPS. The cookie is always secure for a secure request
+	Secure bool
+	// Default net.SameSiteLaxMode
+	SameSite net.SameSite
+}
+
+func (s StartSession) Handle(request inter.Request, next inter.Next) inter.Response {
+	driver, err := s.driver(request)
+	if err != nil {
+		return errorResponse(request, err)
+	}
+	cookie, _ := request.CookieE(s.cookieName())
+	id, data, err := driver.Load(cookie, s.lifetime())
+	if err != nil {
+		panic(err)
+	}
+
+	current := session.NewSession(id, data)
+	request.App().Bind("session", current)
+
+	response := next(request)
+
+	s.save(request, driver, current, response)
+
+	return response
+}
+
+// Terminate removes the expired sessions by the chance of Lottery, so the
+// client doesn't wait for the storage to be scanned
+func (s StartSession) Terminate(request inter.Request, _ inter.Response) {
+	driver, err := s.driver(request)
+	if err != nil {
+		return
+	}
+
+	s.collectGarbage(driver)
+}
+
+func (s StartSession) save(request inter.Request, driver session.Driver, current *session.Session, response inter.Response) {
+	current.AgeFlashData()
+	if current.PreviousId() != "" {
+		if err := driver.Destroy(current.PreviousId()); err != nil {
+			panic(err)
+		}
+	}
+
+	data, err := current.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	value, err := driver.Save(current.Id(), data, s.lifetime())
+	if err != nil {
+		panic(err)
+	}
+
+	response.Cookie(net.Cookie{
+		Name:     s.cookieName(),
+		Value:    value,
+		Path:     "/",
+		Expires:  time.Now().Add(s.lifetime()),
+		MaxAge:   int(s.lifetime().Seconds()),
+		Secure:   s.Secure || isSecure(request),
+		HttpOnly: true,
+		SameSite: s.sameSite(),
+	})
+}
+
+func (s StartSession) collectGarbage(driver session.Driver) {
+	lottery := s.Lottery
+	if lottery[1] == 0 {
+		lottery = [2]int{2, 100}
+	}
+	if rand.Intn(lottery[1]) < lottery[0] {
+		if err := driver.Gc(s.lifetime()); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func (s StartSession) driver(request inter.Request) (session.Driver, error) {
+	if s.Driver != nil {
+		return s.Driver, nil
+	}
+	if driver, err := request.App().MakeE("session_driver"); err == nil {
+		if driver, ok := driver.(session.Driver); ok {
+			return driver, nil
+		}
+	}
+
+	key, err := http_helper.AppKey(request.App())
+	if err != nil {
+		return nil, err
+	}
+
+	return session.CookieDriver{Key: key}, nil
+}
+
+func (s StartSession) lifetime() time.Duration {
+	if s.Lifetime == 0 {
+		return 2 * time.Hour
+	}
+	return s.Lifetime
+}
+
+func (s StartSession) cookieName() string {
+	if s.Cookie == "" {
+		return "confetti_session"
+	}
+	return s.Cookie
+}
+
+func (s StartSession) sameSite() net.SameSite {
+	if s.SameSite == 0 {
+		return net.SameSiteLaxMode
+	}
+	return s.SameSite
+}
+
+func isSecure(request inter.Request) bool {
+	if source, ok := request.(interface{ IsSecure() bool }); ok {
+		return source.IsSecure()
+	}
+	return request.Source().TLS != nil
+}
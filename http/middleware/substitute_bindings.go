@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/confetti-framework/contract/inter"
+)
+
+// SubstituteBindings replaces the route parameters with the models of the
+// resolvers registered with routing.Bind and routing.BindType. It is added to
+// every route by the framework and runs after the middlewares that
+// authenticate and throttle the request (see DefaultPriority). A request
+// without access therefore can't find out which models exist.
+type SubstituteBindings struct{}
+
+func (s SubstituteBindings) Handle(request inter.Request, next inter.Next) inter.Response {
+	raw, err := request.App().MakeE("route_bindings")
+	if err != nil {
+		return next(request)
+	}
+
+	if substitute, ok := raw.(func(request inter.Request) error); ok {
+		if err := substitute(request); err != nil {
+			return errorResponse(request, err)
+		}
+	}
+
+	return next(request)
+}
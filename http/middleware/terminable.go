@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation/http/http_helper"
+	"github.com/confetti-framework/syslog/log_level"
+)
+
+// TerminableMiddleware is a middleware with work to do after the response
+// has been sent to the client (e.g. logging or metrics), so the work doesn't
+// add latency to the response.
+type TerminableMiddleware interface {
+	inter.HttpMiddleware
+	Terminate(request inter.Request, response inter.Response)
+}
+
+// Dispatched registers the middlewares the request is sent through, so they
+// can be terminated after the response has been sent.
+func Dispatched(app inter.App, middlewares []inter.HttpMiddleware) {
+	var terminable []TerminableMiddleware
+	if raw, err := app.MakeE("terminable_middlewares"); err == nil {
+		terminable, _ = raw.([]TerminableMiddleware)
+	}
+	for _, middleware := range middlewares {
+		if middleware, ok := middleware.(TerminableMiddleware); ok {
+			terminable = append(terminable, middleware)
+		}
+	}
+
+	app.Bind("terminable_middlewares", terminable)
+}
+
+// Terminate calls the terminable middlewares in the order they handled the
+// request. The response has already been sent, so a panic of a middleware is
+// logged and the next middlewares are still terminated.
+func Terminate(request inter.Request, response inter.Response) {
+	raw, err := request.App().MakeE("terminable_middlewares")
+	if err != nil {
+		return
+	}
+	terminable, _ := raw.([]TerminableMiddleware)
+	for _, middleware := range terminable {
+		terminate(request, response, middleware)
+	}
+}
+
+func terminate(request inter.Request, response inter.Response, middleware TerminableMiddleware) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			logPanic(request.App(), rec)
+		}
+	}()
+
+	middleware.Terminate(request, response)
+}
+
+// logPanic logs a panic that can't be converted to a response anymore
+func logPanic(app inter.App, rec interface{}) {
+	err := errors.WithStack(http_helper.GetErrorFromPanic(rec))
+	level, ok := errors.FindLevel(err)
+	if !ok {
+		level = log_level.ERROR
+	}
+
+	app.Log().LogWith(level, err.Error(), err)
+}
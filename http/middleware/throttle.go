@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation/http/auth"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Throttle limits the number of requests per key with a token bucket. The bucket
+// holds MaxAttempts tokens and refills completely in the Decay duration. The
+// store is resolved from "rate_limit_store" in the container and falls back
+// to an in-memory store.
+//
+// Example:
+//
+//	middleware.Throttle{MaxAttempts: 60, Decay: time.Minute}
+//	middleware.Throttle{MaxAttempts: 1000, Decay: time.Hour, Key: middleware.ThrottleByHeader("X-Api-Key")}
+//	middleware.Throttle{MaxAttempts: 100, Decay: time.Minute, Key: middleware.ThrottleByUser}
+//
+// When the store fails, the request is rejected with RateLimitStoreError.
+type Throttle struct {
+	MaxAttempts int
+	Decay       time.Duration
+	// Key determines who is limited. Default ThrottleByIp
+	Key func(request inter.Request) string
+	// Name separates the buckets of limiters with the same limits
+	Name  string
+	Store RateLimitStore
+}
+
+// ThrottleByIp limits the requests per client IP address
+func ThrottleByIp(request inter.Request) string {
+	return clientIp(request)
+}
+
+// ThrottleByUser limits the requests per authenticated user. Place it after
+// middleware.Authenticate. Requests of guests are limited by IP address.
+func ThrottleByUser(request inter.Request) string {
+	if user, err := auth.FromApp(request.App()); err == nil {
+		return "user:" + fmt.Sprint(user.AuthIdentifier())
+	}
+	return ThrottleByIp(request)
+}
+
+// ThrottleByHeader limits the requests per value of a header (e.g. an API key).
+// Requests without the header are limited by IP address.
+func ThrottleByHeader(key string) func(request inter.Request) string {
+	return func(request inter.Request) string {
+		if value := request.Header(key); value != "" {
+			return key + ":" + value
+		}
+		return ThrottleByIp(request)
+	}
+}
+
+func (t Throttle) Handle(request inter.Request, next inter.Next) inter.Response {
+	limit, err := t.store(request).Take(t.bucketKey(request), t.MaxAttempts, t.Decay)
+	if err != nil {
+		return errorResponse(request, errors.WithStack(RateLimitStoreError.Wrap("%s", err)))
+	}
+
+	if !limit.Allowed {
+		response := errorResponse(request, errors.WithStack(TooManyRequestsError))
+		setRateLimitHeaders(response, t.MaxAttempts, limit)
+		retryAfter := int(math.Ceil(limit.RetryAfter.Seconds()))
+		response.GetHeaders().Set("Retry-After", strconv.Itoa(retryAfter))
+		response.GetHeaders().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(limit.RetryAfter).Unix(), 10))
+		return response
+	}
+
+	response := next(request)
+	setRateLimitHeaders(response, t.MaxAttempts, limit)
+
+	return response
+}
+
+func (t Throttle) bucketKey(request inter.Request) string {
+	key := t.Key
+	if key == nil {
+		key = ThrottleByIp
+	}
+	name := t.Name
+	if name == "" {
+		name = strconv.Itoa(t.MaxAttempts) + "/" + t.Decay.String()
+	}
+
+	return name + "|" + key(request)
+}
+
+func (t Throttle) store(request inter.Request) RateLimitStore {
+	if t.Store != nil {
+		return t.Store
+	}
+	if store, err := request.App().MakeE("rate_limit_store"); err == nil {
+		return store.(RateLimitStore)
+	}
+
+	return defaultRateLimitStore
+}
+
+func setRateLimitHeaders(response inter.Response, maxAttempts int, limit RateLimit) {
+	response.GetHeaders().Set("X-RateLimit-Limit", strconv.Itoa(maxAttempts))
+	response.GetHeaders().Set("X-RateLimit-Remaining", strconv.Itoa(limit.Remaining))
+}
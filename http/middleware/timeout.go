@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"context"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"time"
+)
+
+// Timeout bounds the duration of the request. The context of the request gets
+// a deadline, so database queries are cancelled. When the deadline is
+// exceeded, a 503 response is returned and the response of the controller
+// is discarded when it finishes later. A panic of a late controller is logged.
+//
+// The controller runs with a copy of the app and the request, so a late
+// controller can't interfere with the container while the timeout response is
+// sent. When the controller finishes in time, the app continues with the
+// bindings of the copy. An app that can't be copied runs the controller in the
+// same goroutine and is only bounded by the cancellation of the context.
+//
+// Example:
+//
+//	routing.Get("/report", controllers.Report).Middleware(middleware.Timeout(5 * time.Second))
+type Timeout time.Duration
+
+// isolatedRequest is implemented by requests that can be copied with another app
+type isolatedRequest interface {
+	WithApp(app inter.App) inter.Request
+}
+
+// appCopier is implemented by apps that can copy their container
+type appCopier interface {
+	Copy() inter.App
+}
+
+func (t Timeout) Handle(request inter.Request, next inter.Next) inter.Response {
+	source := request.Source()
+	ctx, cancel := context.WithTimeout(source.Context(), time.Duration(t))
+	defer cancel()
+
+	if contextual, ok := request.(interface {
+		SetContext(ctx context.Context) inter.Request
+	}); ok {
+		contextual.SetContext(ctx)
+	}
+
+	isolated, ok := isolate(request)
+	if !ok {
+		response := next(request)
+		if ctx.Err() != nil {
+			return t.timeoutResponse(request, ctx)
+		}
+		return response
+	}
+
+	// Buffered, so a late controller can finish without blocking
+	responses := make(chan inter.Response, 1)
+	panics := make(chan interface{})
+	abandoned := make(chan struct{})
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				select {
+				case panics <- rec:
+				case <-abandoned:
+					// Nobody can receive the panic anymore. The copy of the
+					// app is used, so the log doesn't race with the request.
+					logPanic(isolated.App(), rec)
+				}
+			}
+		}()
+		responses <- next(isolated)
+	}()
+
+	select {
+	case response := <-responses:
+		request.App().SetContainer(*isolated.App().Container())
+		return response
+	case rec := <-panics:
+		request.App().SetContainer(*isolated.App().Container())
+		panic(rec)
+	case <-ctx.Done():
+		close(abandoned)
+		return t.timeoutResponse(request, ctx)
+	}
+}
+
+func (t Timeout) timeoutResponse(request inter.Request, ctx context.Context) inter.Response {
+	return errorResponse(request, errors.WithStack(TimeoutError.Wrap("%s", ctx.Err())))
+}
+
+// isolate copies the request and the app for the goroutine of the controller
+func isolate(request inter.Request) (inter.Request, bool) {
+	isolated, ok := request.(isolatedRequest)
+	if !ok {
+		return nil, false
+	}
+	app, ok := request.App().(appCopier)
+	if !ok {
+		return nil, false
+	}
+
+	return isolated.WithApp(app.Copy()), true
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation/http/http_helper"
+	"time"
+)
+
+// ValidateSignature rejects requests with a tampered or expired signature as
+// generated by outcome.SignedUrlByName. All requests are rejected when
+// config.App.Key is empty.
+type ValidateSignature struct{}
+
+func (v ValidateSignature) Handle(request inter.Request, next inter.Next) inter.Response {
+	key, err := http_helper.AppKey(request.App())
+	if err != nil {
+		return errorResponse(request, err)
+	}
+
+	source := request.Source()
+	query := source.URL.Query()
+
+	if !http_helper.HasValidSignature(key, source.URL.EscapedPath(), query) {
+		return errorResponse(request, errors.WithStack(InvalidSignatureError))
+	}
+
+	if http_helper.SignatureExpired(query, time.Now()) {
+		return errorResponse(request, errors.WithStack(ExpiredSignatureError))
+	}
+
+	return next(request)
+}
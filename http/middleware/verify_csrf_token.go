@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation/http/method"
+	"github.com/confetti-framework/foundation/http/session"
+	"github.com/confetti-framework/foundation/http/view_helper"
+	"html/template"
+	"strings"
+)
+
+const (
+	csrfField  = "_token"
+	csrfHeader = "X-CSRF-Token"
+)
+
+// The methods of which the CSRF token is verified
+var csrfMethods = []string{method.Post, method.Put, method.Patch, method.Delete}
+
+// VerifyCsrfToken rejects state-changing requests without the CSRF token of the
+// session with status 419. The token is read from the _token form field or the
+// X-CSRF-Token header. Add the middleware after middleware.StartSession.
+//
+// The form fields are read in order until the _token field is found. Browsers
+// send the fields in the order of the form, so place csrf_field before the file
+// inputs of a large upload. A _token field that ends after the first
+// MaxFormSize bytes is ignored, then use the X-CSRF-Token header.
+//
+// The token is available in templates by the csrf_token and csrf_field functions:
+//
+//	<form method="POST" action="/profile">
+//		{{ csrf_field }}
+//	</form>
+type VerifyCsrfToken struct {
+	// The paths to exclude (e.g. webhooks). A path that ends with * is used as prefix.
+	Except []string
+}
+
+func (v VerifyCsrfToken) Handle(request inter.Request, next inter.Next) inter.Response {
+	current, err := session.FromApp(request.App())
+	if err != nil {
+		return errorResponse(request, err)
+	}
+
+	token := current.Token()
+	view_helper.AddTemplateFunctions(request.App(), template.FuncMap{
+		"csrf_token": func() string {
+			return token
+		},
+		"csrf_field": func() template.HTML {
+			return template.HTML(fmt.Sprintf(
+				`<input type="hidden" name="%s" value="%s">`,
+				csrfField,
+				template.HTMLEscapeString(token),
+			))
+		},
+	})
+
+	if !v.shouldVerify(request) || tokensMatch(token, v.requestToken(request)) {
+		return next(request)
+	}
+
+	return errorResponse(request, errors.WithStack(CsrfTokenMismatchError))
+}
+
+func (v VerifyCsrfToken) shouldVerify(request inter.Request) bool {
+	if !inMethods(request.Method(), csrfMethods) {
+		return false
+	}
+
+	path := "/" + strings.Trim(request.Path(), "/")
+	for _, except := range v.Except {
+		except = "/" + strings.Trim(except, "/")
+		if strings.HasSuffix(except, "*") && strings.HasPrefix(path, strings.TrimSuffix(except, "*")) {
+			return false
+		}
+		if path == except {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (v VerifyCsrfToken) requestToken(request inter.Request) string {
+	if token := request.Header(csrfHeader); token != "" {
+		return token
+	}
+	return formValue(request, csrfField)
+}
+
+func tokensMatch(expected string, actual string) bool {
+	return actual != "" && subtle.ConstantTimeCompare([]byte(expected), []byte(actual)) == 1
+}
+
+func inMethods(needle string, methods []string) bool {
+	for _, item := range methods {
+		if needle == item {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,228 @@
+package openapi
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/http/method"
+	"github.com/confetti-framework/foundation/http/routing"
+	net "net/http"
+	"strconv"
+	"strings"
+)
+
+const Version = "3.1.0"
+
+// The pattern of an optional parameter after decoration
+const optionalPattern = ":.*}"
+
+type Document struct {
+	OpenApi    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components,omitempty"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem contains the operations of a path by lowercase method
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationId string              `json:"operationId,omitempty"`
+	Summary     string              `json:"summary,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// Generate creates an OpenAPI document of the routes. The request and
+// response bodies are described by the types registered with
+// RouteCollection.Accepts and RouteCollection.Returns.
+func Generate(routes []inter.Route, info Info) Document {
+	schemas := NewSchemas()
+	document := Document{OpenApi: Version, Info: info, Paths: map[string]PathItem{}}
+	operationIds := newOperationIds(routes)
+
+	for _, route := range routes {
+		if route.Method() == method.Head {
+			continue
+		}
+
+		operationId := operationIds.of(route)
+		for i, variant := range pathVariants(routing.DisplayTemplate(route)) {
+			path, parameters := pathParameters(variant)
+			operation := newOperation(route, schemas, parameters)
+			operation.OperationId = operationId
+			if i > 0 && operationId != "" {
+				operation.OperationId = operationIds.unique(operationId)
+			}
+
+			if document.Paths[path] == nil {
+				document.Paths[path] = PathItem{}
+			}
+			document.Paths[path][strings.ToLower(route.Method())] = operation
+		}
+	}
+
+	document.Components.Schemas = schemas.Components
+
+	return document
+}
+
+func newOperation(route inter.Route, schemas *Schemas, parameters []Parameter) *Operation {
+	operation := &Operation{Parameters: parameters, Responses: map[string]Response{}}
+
+	var documentation routing.Documentation
+	if documented, ok := route.(interface{ Documentation() routing.Documentation }); ok {
+		documentation = documented.Documentation()
+	}
+	operation.Summary = documentation.Summary
+	operation.Tags = documentation.Tags
+
+	if documentation.Request != nil {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content:  jsonContent(schemas.Of(documentation.Request)),
+		}
+	}
+	for status, body := range documentation.Responses {
+		response := Response{Description: net.StatusText(status)}
+		if body != nil {
+			response.Content = jsonContent(schemas.Of(body))
+		}
+		operation.Responses[strconv.Itoa(status)] = response
+	}
+	if len(operation.Responses) == 0 {
+		operation.Responses["200"] = Response{Description: net.StatusText(net.StatusOK)}
+	}
+
+	return operation
+}
+
+// operationIds keeps the operation ids unique. Routes that share a name (e.g.
+// PUT and PATCH of a resource) get the method as suffix.
+type operationIds struct {
+	shared map[string]bool
+	used   map[string]bool
+}
+
+func newOperationIds(routes []inter.Route) *operationIds {
+	ids := &operationIds{shared: map[string]bool{}, used: map[string]bool{}}
+	seen := map[string]bool{}
+	for _, route := range routes {
+		if route.Method() == method.Head || route.Name() == "" {
+			continue
+		}
+		ids.shared[route.Name()] = seen[route.Name()]
+		seen[route.Name()] = true
+	}
+
+	return ids
+}
+
+func (o *operationIds) of(route inter.Route) string {
+	id := route.Name()
+	if id == "" {
+		return ""
+	}
+	if o.shared[id] {
+		id += "." + strings.ToLower(route.Method())
+	}
+
+	return o.unique(id)
+}
+
+func (o *operationIds) unique(id string) string {
+	result := id
+	for i := 2; o.used[result]; i++ {
+		result = id + "." + strconv.Itoa(i)
+	}
+	o.used[result] = true
+
+	return result
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+// pathVariants splits a template with optional parameters (as decorated by
+// route_decorator.OptionalParameter) into the template with all parameters
+// and the templates without the optional parameters, because a path
+// parameter is always required in OpenAPI.
+func pathVariants(template string) []string {
+	variants := []string{template}
+	for {
+		i := strings.LastIndex(template, optionalPattern)
+		if i == -1 {
+			return variants
+		}
+		template = strings.TrimRight(template[:strings.LastIndex(template[:i], "{")], "/")
+		if template == "" {
+			template = "/"
+		}
+		variants = append(variants, template)
+	}
+}
+
+// pathParameters removes the regular expressions from the template. The
+// regular expressions are added to the schemas of the parameters.
+func pathParameters(template string) (string, []Parameter) {
+	var path strings.Builder
+	var parameters []Parameter
+	level, start := 0, 0
+
+	for i := 0; i < len(template); i++ {
+		switch {
+		case template[i] == '{':
+			if level == 0 {
+				start = i
+			}
+			level++
+		case template[i] == '}' && level > 0:
+			level--
+			if level > 0 {
+				continue
+			}
+			parts := strings.SplitN(template[start+1:i], ":", 2)
+			schema := &Schema{Type: "string"}
+			if len(parts) == 2 && ":"+parts[1]+"}" != optionalPattern {
+				schema.Pattern = "^" + parts[1] + "$"
+			}
+			parameters = append(parameters, Parameter{Name: parts[0], In: "path", Required: true, Schema: schema})
+			path.WriteString("{" + parts[0] + "}")
+		case level == 0:
+			path.WriteByte(template[i])
+		}
+	}
+
+	return path.String(), parameters
+}
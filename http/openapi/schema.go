@@ -0,0 +1,189 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Schema is a JSON Schema (draft 2020-12) as used by OpenAPI 3.1
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	rawJsonType   = reflect.TypeOf(json.RawMessage{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	// The key of a component may only contain these characters
+	invalidKeyCharacters = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+)
+
+// Schemas reflects Go types into JSON Schemas. Named structs are added to the
+// components, so they are described once and can refer to themselves.
+type Schemas struct {
+	Components map[string]*Schema
+	// The types of the components by key
+	types map[string]reflect.Type
+}
+
+func NewSchemas() *Schemas {
+	return &Schemas{Components: map[string]*Schema{}, types: map[string]reflect.Type{}}
+}
+
+// Of returns the schema of the type of the value
+func (s *Schemas) Of(value interface{}) *Schema {
+	return s.ofType(reflect.TypeOf(value))
+}
+
+func (s *Schemas) ofType(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawJsonType:
+		return &Schema{}
+	case t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType):
+		// The JSON representation is unknown
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", ContentEncoding: "base64"}
+		}
+		return &Schema{Type: "array", Items: s.ofType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.ofType(t.Elem())}
+	case reflect.Struct:
+		return s.ofStruct(t)
+	}
+
+	// Interfaces accept any value
+	return &Schema{}
+}
+
+func (s *Schemas) ofStruct(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return s.structSchema(t)
+	}
+
+	key := s.componentKey(t)
+	ref := &Schema{Ref: "#/components/schemas/" + key}
+	if _, ok := s.Components[key]; ok {
+		return ref
+	}
+
+	// Register the component first, so recursive types refer to it
+	s.types[key] = t
+	s.Components[key] = &Schema{}
+	*s.Components[key] = *s.structSchema(t)
+
+	return ref
+}
+
+// componentKey returns the name of the type. When another type with the
+// same name is registered, the name is qualified with the package.
+func (s *Schemas) componentKey(t reflect.Type) string {
+	name := invalidKeyCharacters.ReplaceAllString(t.Name(), "_")
+	keys := []string{
+		name,
+		path.Base(t.PkgPath()) + "." + name,
+		invalidKeyCharacters.ReplaceAllString(t.PkgPath(), "_") + "." + name,
+	}
+	for _, key := range keys {
+		if registered, ok := s.types[key]; !ok || registered == t {
+			return key
+		}
+	}
+
+	return keys[len(keys)-1]
+}
+
+func (s *Schemas) structSchema(t reflect.Type) *Schema {
+	result := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	s.addFields(result, t)
+
+	return result
+}
+
+// addFields adds the fields the same way as encoding/json encodes them
+func (s *Schemas) addFields(result *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitEmpty, skip := jsonField(field)
+		if skip {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && fieldType.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			s.addFields(result, fieldType)
+			continue
+		}
+
+		result.Properties[name] = s.ofType(field.Type)
+		if !omitEmpty && field.Type.Kind() != reflect.Ptr {
+			result.Required = append(result.Required, name)
+		}
+	}
+}
+
+func jsonField(field reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	if field.PkgPath != "" && !field.Anonymous {
+		return "", false, true
+	}
+
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return name, omitEmpty, false
+}
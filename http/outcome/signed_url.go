@@ -0,0 +1,46 @@
+package outcome
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/http/http_helper"
+	"net/url"
+	"time"
+)
+
+// Receive a signed URL to a named route by app, name, uri parameters and an
+// expiration time. A zero expiration time creates a link that never expires.
+// The signature is calculated with the key in config.App.Key. It panics when
+// the key is empty.
+func SignedUrlByName(
+	app inter.App,
+	name string,
+	parameters Parameters,
+	expiresAt time.Time,
+	queryParameters ...Parameters,
+) string {
+	query := Parameters{}
+	if len(queryParameters) > 0 {
+		for key, value := range queryParameters[0] {
+			query[key] = value
+		}
+	}
+	if !expiresAt.IsZero() {
+		query[http_helper.ExpiresKey] = expiresAt.Unix()
+	}
+
+	raw := UrlByName(app, name, parameters, query)
+	result, err := url.Parse(raw)
+	if err != nil {
+		panic("URL cannot be signed because " + err.Error())
+	}
+
+	key, err := http_helper.AppKey(app)
+	if err != nil {
+		panic("URL cannot be signed because " + err.Error())
+	}
+	values := result.Query()
+	values.Set(http_helper.SignatureKey, http_helper.Signature(key, result.EscapedPath(), values))
+	result.RawQuery = values.Encode()
+
+	return result.String()
+}
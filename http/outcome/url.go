@@ -0,0 +1,190 @@
+package outcome
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/support"
+	"net/url"
+	"strings"
+)
+
+// UrlGenerator generates absolute URLs. The root URL (scheme and host) is
+// read from config.App.Url and falls back to the scheme and host of the
+// current request. Assets use config.App.AssetUrl if configured.
+type UrlGenerator struct {
+	app inter.AppReader
+}
+
+func NewUrlGenerator(app inter.AppReader) *UrlGenerator {
+	return &UrlGenerator{app: app}
+}
+
+// Url returns the UrlGenerator bound as "url" in the container
+func Url(app inter.AppReader) *UrlGenerator {
+	if generator, err := app.MakeE("url"); err == nil {
+		return generator.(*UrlGenerator)
+	}
+
+	return NewUrlGenerator(app)
+}
+
+// To generates an absolute URL to the given path
+func (u UrlGenerator) To(path string, query ...Parameters) string {
+	result := path
+	if !isAbsoluteUrl(path) {
+		result = u.root() + "/" + strings.TrimLeft(path, "/")
+	}
+
+	if len(query) > 0 && len(query[0]) > 0 {
+		separator := "?"
+		if strings.Contains(result, "?") {
+			separator = "&"
+		}
+		result += separator + encodeQuery(query[0])
+	}
+
+	return result
+}
+
+// Route generates an absolute URL to a named route by uri parameters and query
+// parameters. It panics if the URL cannot be generated.
+func (u UrlGenerator) Route(name string, parameters ...Parameters) string {
+	result, err := u.RouteE(name, parameters...)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+func (u UrlGenerator) RouteE(name string, parameters ...Parameters) (string, error) {
+	raw, err := urlByNameE(u.app, name, parameters...)
+	if err != nil {
+		return "", errors.WithMessage(err, "URL cannot be generated")
+	}
+
+	result, err := url.Parse(raw)
+	if err != nil {
+		return "", errors.WithMessage(errors.WithStack(err), "URL cannot be generated")
+	}
+
+	// A route without a domain is generated as path
+	if result.Host == "" {
+		return u.To(raw), nil
+	}
+	if scheme := u.forcedScheme(); scheme != "" {
+		result.Scheme = scheme
+	}
+
+	return result.String(), nil
+}
+
+// Current returns the absolute URL of the current request including the query
+func (u UrlGenerator) Current() string {
+	request, err := u.request()
+	if err != nil {
+		return u.To("/")
+	}
+	source := request.Source()
+
+	return u.To(source.URL.RequestURI())
+}
+
+// Previous returns the URL from the Referer header. Without the header, or when
+// the referer is another host than the app, the URL of the fallback path or
+// the root URL is returned.
+func (u UrlGenerator) Previous(fallback ...string) string {
+	if request, err := u.request(); err == nil {
+		if referer := request.Header("Referer"); u.isOwnUrl(referer) {
+			return referer
+		}
+	}
+	if len(fallback) > 0 {
+		return u.To(fallback[0])
+	}
+
+	return u.To("/")
+}
+
+// Asset generates the URL to an asset. Configure config.App.AssetUrl to
+// serve the assets from another host (e.g. a CDN).
+func (u UrlGenerator) Asset(path string) string {
+	if isAbsoluteUrl(path) {
+		return path
+	}
+	root := u.config("config.App.AssetUrl")
+	if root == "" {
+		root = u.root()
+	}
+
+	return strings.TrimRight(root, "/") + "/" + strings.TrimLeft(path, "/")
+}
+
+// root returns the scheme and host without a trailing slash
+func (u UrlGenerator) root() string {
+	if root := u.config("config.App.Url"); root != "" {
+		return strings.TrimRight(root, "/")
+	}
+
+	request, err := u.request()
+	if err != nil {
+		return ""
+	}
+	current, err := url.Parse(request.Url())
+	if err != nil || current.Host == "" {
+		return ""
+	}
+
+	return current.Scheme + "://" + current.Host
+}
+
+// isOwnUrl determines whether the URL is absolute and has the host of the app
+func (u UrlGenerator) isOwnUrl(raw string) bool {
+	target, err := url.Parse(raw)
+	if err != nil || target.Host == "" {
+		return false
+	}
+	root, err := url.Parse(u.root())
+	if err != nil || root.Host == "" {
+		return false
+	}
+
+	return strings.EqualFold(target.Host, root.Host)
+}
+
+func (u UrlGenerator) forcedScheme() string {
+	root, err := url.Parse(u.config("config.App.Url"))
+	if err != nil {
+		return ""
+	}
+	return root.Scheme
+}
+
+func (u UrlGenerator) request() (inter.Request, error) {
+	request, err := u.app.MakeE("request")
+	if err != nil {
+		return nil, err
+	}
+	return request.(inter.Request), nil
+}
+
+func (u UrlGenerator) config(key string) string {
+	value, err := u.app.MakeE(key)
+	if err != nil || value == nil {
+		return ""
+	}
+	return support.NewValue(value).String()
+}
+
+func isAbsoluteUrl(path string) bool {
+	return strings.HasPrefix(path, "//") || strings.Contains(path, "://")
+}
+
+func encodeQuery(parameters Parameters) string {
+	values := url.Values{}
+	for key, value := range parameters {
+		values.Set(key, support.NewValue(value).String())
+	}
+
+	return values.Encode()
+}
@@ -8,19 +8,32 @@ import (
 	"github.com/confetti-framework/foundation/http/http_helper"
 	"github.com/confetti-framework/foundation/http/method"
 	"github.com/confetti-framework/support"
+	"strings"
 )
 
 type Parameters map[string]interface{}
 
 // Receive the URL to a named route by app, name, uri parameters, query parameters
 func UrlByName(app inter.App, name string, parameters ...Parameters) string {
+	result, err := urlByNameE(app, name, parameters...)
+	if err != nil {
+		panic("URL cannot be generated because " + err.Error())
+	}
+
+	return result
+}
+
+func urlByNameE(app inter.AppReader, name string, parameters ...Parameters) (string, error) {
 	var pairs []string
 	var result fmt.Stringer
 
-	routes := app.Make("routes").(inter.RouteCollection)
-	route, err := RouteByName(routes, name)
+	routes, err := app.MakeE("routes")
 	if err != nil {
-		panic("URL cannot be generated because " + err.Error())
+		return "", err
+	}
+	route, err := RouteByName(routes.(inter.RouteCollection), name)
+	if err != nil {
+		return "", err
 	}
 
 	UriParameters := Parameters{}
@@ -33,10 +46,10 @@ func UrlByName(app inter.App, name string, parameters ...Parameters) string {
 		QueryParameters = parameters[1]
 	}
 
-	// Remove Confetti custom placeholder
-	route = route_decorator.UriSuffixSlash{}.Revert(route)
+	// Remove Confetti custom placeholder without changing the registered route
+	uri := route_decorator.UriSuffixSlash{}.RevertUri(route.Uri())
 
-	muxRoute := http_helper.MuxFromRoute(route)
+	muxRoute := http_helper.MuxFromTemplates(route.RouteOptions().Prefixes(), uri, route.Domain())
 
 	for name, value := range UriParameters {
 		pairs = append(pairs, name, support.NewValue(value).String())
@@ -53,19 +66,25 @@ func UrlByName(app inter.App, name string, parameters ...Parameters) string {
 	muxRoute.Schemes("https")
 
 	result, err = muxRoute.URL(pairs...)
-
 	if err != nil {
-		panic("URL cannot be generated because " + err.Error())
+		return "", errors.WithStack(err)
 	}
 
-	return result.String()
+	return result.String(), nil
 }
 
-// Receive inter.Route by name
+// Receive inter.Route by name. A route registered with multiple methods
+// (e.g. PUT and PATCH) is counted once.
 func RouteByName(routes inter.RouteCollection, name string) (inter.Route, error) {
 	var matchedRoutes []inter.Route
+	urls := map[string]bool{}
 	for _, route := range routes.All() {
-		if route.Method() != method.Head && name == route.Name() {
+		if route.Method() == method.Head || name != route.Name() {
+			continue
+		}
+		url := routeUrlKey(route)
+		if !urls[url] {
+			urls[url] = true
 			matchedRoutes = append(matchedRoutes, route)
 		}
 	}
@@ -80,3 +99,7 @@ func RouteByName(routes inter.RouteCollection, name string) (inter.Route, error)
 
 	return matchedRoutes[0], nil
 }
+
+func routeUrlKey(route inter.Route) string {
+	return route.Domain() + strings.Join(route.RouteOptions().Prefixes(), "") + route.Uri()
+}
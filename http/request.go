@@ -2,17 +2,23 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"github.com/confetti-framework/contract/inter"
 	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation/http/auth"
+	"github.com/confetti-framework/foundation/http/http_helper"
 	"github.com/confetti-framework/foundation/http/method"
+	"github.com/confetti-framework/foundation/http/session"
 	"github.com/confetti-framework/support"
 	"github.com/gorilla/mux"
 	"io"
 	"io/ioutil"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"strings"
 )
 
@@ -21,16 +27,21 @@ const (
 )
 
 type Request struct {
-	app       inter.App
-	source    http.Request
-	urlValues support.Map
-	content   support.Value
+	app          inter.App
+	source       http.Request
+	urlValues    support.Map
+	domainValues support.Map
+	content      support.Value
+
+	// The method before it was changed by SetMethod
+	originalMethod string
 }
 
 type Options struct {
-	App     inter.App
-	Source  http.Request
-	Method  string
+	App    inter.App
+	Source http.Request
+	Method string
+	// The host, optionally with the scheme (e.g. "https://example.com")
 	Host    string
 	Url     string
 	Header  http.Header
@@ -62,6 +73,12 @@ func NewRequest(options Options) inter.Request {
 
 		if options.Host != "" {
 			source.Host = options.Host
+			// The host may contain the scheme (e.g. "https://example.com")
+			if host, err := url.Parse(options.Host); err == nil && host.Scheme != "" && host.Host != "" {
+				source.Host = host.Host
+				source.URL.Scheme = host.Scheme
+				source.URL.Host = host.Host
+			}
 		}
 
 		if options.Body != nil {
@@ -106,6 +123,17 @@ func (r *Request) SetApp(app inter.App) {
 	r.app = app
 }
 
+// WithApp returns a copy of the request with another app. The source request
+// and the parameters are copied, so the copy can be used in another goroutine.
+func (r Request) WithApp(app inter.App) inter.Request {
+	r.app = app
+	r.source = *r.source.Clone(r.source.Context())
+	r.urlValues = copyMap(r.urlValues)
+	r.domainValues = copyMap(r.domainValues)
+
+	return &r
+}
+
 func (r *Request) Make(abstract interface{}) interface{} {
 	return r.App().Make(abstract)
 }
@@ -118,6 +146,12 @@ func (r Request) Source() http.Request {
 	return r.source
 }
 
+// SetContext replaces the context of the source request (e.g. to add a deadline)
+func (r *Request) SetContext(ctx context.Context) inter.Request {
+	r.source = *r.source.WithContext(ctx)
+	return r
+}
+
 func (r Request) Method() string {
 	if r.source.Method == "" {
 		return method.Get
@@ -126,16 +160,124 @@ func (r Request) Method() string {
 	return r.source.Method
 }
 
+// SetMethod overrides the method of the request (e.g. for method spoofing)
+func (r *Request) SetMethod(method string) inter.Request {
+	if r.originalMethod == "" {
+		r.originalMethod = r.source.Method
+	}
+	r.source.Method = method
+	return r
+}
+
+// OriginalMethod returns the method of the request before it was changed
+// (e.g. by middleware.MethodOverride)
+func (r Request) OriginalMethod() string {
+	if r.originalMethod != "" {
+		return r.originalMethod
+	}
+	return r.source.Method
+}
+
 func (r Request) Path() string {
 	return r.source.URL.Path
 }
 
+// Url returns the URL without the query string. The scheme and host
+// are taken from the trusted proxies if present.
 func (r Request) Url() string {
-	return r.source.URL.Scheme + r.source.Host + r.source.URL.Path
+	return r.Scheme() + "://" + r.httpHost() + r.source.URL.Path
 }
 
+// FullUrl returns the URL including the query string
 func (r Request) FullUrl() string {
-	return r.source.URL.Scheme + r.source.Host + r.source.RequestURI
+	if r.source.URL.RawQuery == "" {
+		return r.Url()
+	}
+	return r.Url() + "?" + r.source.URL.RawQuery
+}
+
+// Ip returns the client IP address. Addresses provided by the Forwarded or
+// X-Forwarded-For headers are only used when the request comes from a
+// trusted proxy.
+func (r Request) Ip() string {
+	return r.Ips()[0]
+}
+
+// Ips returns the client IP addresses, starting with the address closest to
+// the application. Trusted proxies are left out. The first address is the
+// most trusted one, the last address is the least trusted one.
+func (r Request) Ips() []string {
+	remote := http_helper.StripPort(r.source.RemoteAddr)
+	trusted := r.trustedProxies()
+	if !http_helper.IsTrustedProxy(remote, trusted) {
+		return []string{remote}
+	}
+
+	var addresses []string
+	for _, hop := range http_helper.ForwardedHops(r.source) {
+		address := http_helper.StripPort(hop.For)
+		if net.ParseIP(address) != nil {
+			addresses = append(addresses, address)
+		}
+	}
+	addresses = append(addresses, remote)
+
+	var result []string
+	for i := len(addresses) - 1; i >= 0; i-- {
+		if !http_helper.IsTrustedProxy(addresses[i], trusted) {
+			result = append(result, addresses[i])
+		}
+	}
+
+	// If all addresses are trusted, the first one is the client
+	if len(result) == 0 {
+		result = append(result, addresses[0])
+	}
+
+	return result
+}
+
+// Scheme returns "http" or "https"
+func (r Request) Scheme() string {
+	if proto := r.forwarded().Proto; proto == "http" || proto == "https" {
+		return proto
+	}
+	if r.source.URL.Scheme != "" {
+		return strings.ToLower(r.source.URL.Scheme)
+	}
+	if r.source.TLS != nil {
+		return "https"
+	}
+
+	return "http"
+}
+
+// IsSecure determines whether the request is made over HTTPS
+func (r Request) IsSecure() bool {
+	return r.Scheme() == "https"
+}
+
+// Host returns the host name without the port
+func (r Request) Host() string {
+	return http_helper.StripPort(r.rawHost())
+}
+
+// Port returns the port the client connected to
+func (r Request) Port() int {
+	if port, err := strconv.Atoi(r.forwarded().Port); err == nil {
+		return port
+	}
+
+	_, rawPort, err := net.SplitHostPort(r.rawHost())
+	if port, portErr := strconv.Atoi(rawPort); err == nil && portErr == nil {
+		return port
+	}
+
+	if r.IsSecure() {
+		return 443
+	}
+
+	return 80
 }
 
 func (r Request) Body() string {
@@ -157,6 +299,14 @@ func (r *Request) SetBody(body string) inter.Request {
 	return r
 }
 
+// SetBodyReader replaces the body of the source request without reading it
+func (r *Request) SetBodyReader(body io.ReadCloser) inter.Request {
+	r.source.Body = body
+	r.content = support.NewValue(nil)
+
+	return r
+}
+
 func (r *Request) Content(keyInput ...string) support.Value {
 	result, err := r.ContentE(keyInput...)
 	if err != nil {
@@ -214,11 +364,52 @@ func (r Request) ParameterOr(key string, defaultValue interface{}) support.Value
 	return value
 }
 
+// Domain returns a parameter of the domain of the route (e.g. {tenant}.example.com)
+func (r Request) Domain(key string) support.Value {
+	result, err := r.DomainE(key)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r Request) DomainE(key string) (support.Value, error) {
+	value, err := r.domainValues.GetE(key)
+	if err != nil {
+		err = errors.Wrap(err, "from domain parameter")
+	}
+	return value, err
+}
+
+func (r Request) DomainOr(key string, defaultValue interface{}) support.Value {
+	value, err := r.DomainE(key)
+	if err != nil {
+		return support.NewValue(defaultValue)
+	}
+	return value
+}
+
+// SetDomainValues stores the parameters of the domain separately. The
+// parameters are also available as route parameter.
+func (r *Request) SetDomainValues(vars map[string]string) inter.Request {
+	r.domainValues = support.NewMap(vars)
+	return r
+}
+
 func (r *Request) SetUrlValues(vars map[string]string) inter.Request {
 	r.urlValues = support.NewMap(vars)
 	return r
 }
 
+// SetParameter replaces a route parameter with a resolved value (e.g. a model)
+func (r *Request) SetParameter(key string, value interface{}) inter.Request {
+	if r.urlValues == nil {
+		r.urlValues = support.Map{}
+	}
+	r.urlValues[key] = support.NewValue(value)
+	return r
+}
+
 func (r Request) Query(key string) support.Value {
 	result, err := r.QueryE(key)
 	if err != nil {
@@ -270,6 +461,50 @@ func (r Request) CookieE(key string) (string, error) {
 	return result, err
 }
 
+// User returns the user authenticated by middleware.Authenticate
+func (r Request) User() auth.User {
+	result, err := r.UserE()
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r Request) UserE() (auth.User, error) {
+	return auth.FromApp(r.App())
+}
+
+// Can determines whether the user may perform the ability of the auth.Gate
+func (r Request) Can(ability string, arguments ...interface{}) bool {
+	user, err := r.UserE()
+	if err != nil {
+		return false
+	}
+	return auth.GateFromApp(r.App()).Allows(user, ability, arguments...)
+}
+
+// Authorize returns auth.ForbiddenError if the user may not perform the ability
+func (r Request) Authorize(ability string, arguments ...interface{}) error {
+	user, err := r.UserE()
+	if err != nil {
+		return err
+	}
+	return auth.GateFromApp(r.App()).Authorize(user, ability, arguments...)
+}
+
+// Session returns the session started by middleware.StartSession
+func (r Request) Session() *session.Session {
+	result, err := r.SessionE()
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r Request) SessionE() (*session.Session, error) {
+	return session.FromApp(r.App())
+}
+
 func (r *Request) File(key string) support.File {
 	file, err := r.FileE(key)
 	if err != nil {
@@ -319,6 +554,53 @@ func (r Request) Route() inter.Route {
 	return r.app.Make("route").(inter.Route)
 }
 
+func (r Request) trustedProxies() []*net.IPNet {
+	if r.app == nil {
+		return nil
+	}
+	raw, err := r.app.MakeE("config.App.TrustedProxies")
+	if err != nil || raw == nil {
+		return nil
+	}
+
+	var proxies []string
+	for _, proxy := range support.NewValue(raw).Collection() {
+		proxies = append(proxies, proxy.String())
+	}
+
+	return http_helper.TrustedProxies(proxies)
+}
+
+// forwarded returns the values of the trusted proxies
+func (r Request) forwarded() http_helper.Forwarded {
+	return http_helper.TrustedForwarded(r.source, r.trustedProxies())
+}
+
+// rawHost returns the host including the port if given
+func (r Request) rawHost() string {
+	if host := r.forwarded().Host; host != "" {
+		return host
+	}
+	if r.source.Host != "" {
+		return r.source.Host
+	}
+
+	return r.source.URL.Host
+}
+
+// httpHost returns the host with the port if it is not the default port of the scheme
+func (r Request) httpHost() string {
+	port := r.Port()
+	if (r.Scheme() == "http" && port == 80) || (r.Scheme() == "https" && port == 443) {
+		if strings.Contains(r.Host(), ":") {
+			return "[" + r.Host() + "]"
+		}
+		return r.Host()
+	}
+
+	return net.JoinHostPort(r.Host(), strconv.Itoa(port))
+}
+
 func (r Request) parameters() support.Map {
 	urlMap := r.urlValues
 	queryMap := support.NewMap(r.Source().URL.Query())
@@ -353,3 +635,15 @@ func (r *Request) getFilesByHeaders(fileHeaders []*multipart.FileHeader) ([]supp
 	}
 	return result, nil
 }
+
+func copyMap(values support.Map) support.Map {
+	if values == nil {
+		return nil
+	}
+	result := support.Map{}
+	for key, value := range values {
+		result[key] = value
+	}
+
+	return result
+}
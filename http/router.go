@@ -16,15 +16,63 @@ func NewRouter(app inter.App) Router {
 	return Router{routes: routes}
 }
 
+// DispatchToRoute sends the request through the global middlewares before the
+// route is matched. Global middlewares can therefore change the request (e.g.
+// the method) that the route is matched by. The maintenance mode is checked
+// before all middlewares, so no middleware runs while the application is down.
 func (r Router) DispatchToRoute(request inter.Request) inter.Response {
+	middlewares := globalMiddlewares(request.App())
+	middleware.Dispatched(request.App(), middlewares)
+
+	return middleware.NewPipeline(request.App()).
+		Send(request).
+		Through(append([]inter.HttpMiddleware{middleware.CheckForMaintenanceMode{}}, middlewares...)).
+		Then(r.dispatch)
+}
+
+func (r Router) dispatch(request inter.Request) inter.Response {
 	r.currentRequest = request
 
 	route := r.routes.Match(request)
 
-	middlewares := allMiddlewares(route.Middleware())
+	middlewares := allMiddlewares(middleware.Resolve(request.App(), routeMiddlewares(route), excludedMiddleware(route)))
+	middleware.Dispatched(request.App(), middlewares)
 
 	return middleware.NewPipeline(request.App()).
 		Send(request).
 		Through(middlewares).
 		Then(route.Controller())
 }
+
+// The global middlewares are bound as "global_middlewares" and apply to all
+// requests, also to requests without route.
+func globalMiddlewares(app inter.App) []inter.HttpMiddleware {
+	raw, err := app.MakeE("global_middlewares")
+	if err != nil {
+		return nil
+	}
+
+	return middleware.Resolve(app, raw.([]inter.HttpMiddleware), nil)
+}
+
+// routeMiddlewares adds SubstituteBindings to the middlewares of the route. It
+// is moved after authentication by the priority of the middlewares.
+func routeMiddlewares(route inter.Route) []inter.HttpMiddleware {
+	middlewares := route.Middleware()
+	for _, current := range middlewares {
+		if middleware.Name(current) == middleware.Name(middleware.SubstituteBindings{}) {
+			return middlewares
+		}
+	}
+
+	return append(append([]inter.HttpMiddleware{}, middlewares...), middleware.SubstituteBindings{})
+}
+
+func excludedMiddleware(route inter.Route) []inter.HttpMiddleware {
+	if excluded, ok := route.(interface {
+		ExcludedMiddleware() []inter.HttpMiddleware
+	}); ok {
+		return excluded.ExcludedMiddleware()
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package routing
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Resolver converts the value of a route parameter to a domain object. Return
+// ModelNotFoundError (or an error that wraps it) if the object does not exist.
+// The resolvers of the matched route are called by middleware.SubstituteBindings.
+type Resolver func(value string, request inter.Request) (interface{}, error)
+
+// parameterBinder is implemented by requests that can store resolved parameters
+type parameterBinder interface {
+	SetParameter(key string, value interface{}) inter.Request
+}
+
+// Bind returns a collection that resolves the route parameter by the resolver.
+// Merge it with other routes by using Group.
+func Bind(parameter string, resolver Resolver) *RouteCollection {
+	return NewRouteCollection().Bind(parameter, resolver)
+}
+
+// BindType resolves the route parameter named after the type of the model.
+// E.g. BlogPost{} resolves the parameter {blog_post}.
+func BindType(model interface{}, resolver Resolver) *RouteCollection {
+	return NewRouteCollection().BindType(model, resolver)
+}
+
+// Bind registers a resolver for the route parameter
+func (c *RouteCollection) Bind(parameter string, resolver Resolver) *RouteCollection {
+	if c.resolvers == nil {
+		c.resolvers = map[string]Resolver{}
+	}
+	c.resolvers[parameter] = resolver
+
+	return c
+}
+
+// BindType registers a resolver for the route parameter named after the type of the model
+func (c *RouteCollection) BindType(model interface{}, resolver Resolver) *RouteCollection {
+	return c.Bind(parameterByType(model), resolver)
+}
+
+// Replace the raw route parameters with the resolved objects
+func (c *RouteCollection) resolveParameters(request inter.Request, vars map[string]string) error {
+	for parameter, resolver := range c.resolvers {
+		value, ok := vars[parameter]
+		if !ok {
+			continue
+		}
+
+		binder, ok := request.(parameterBinder)
+		if !ok {
+			return errors.New("request can't hold resolved route parameter " + parameter)
+		}
+
+		model, err := resolver(value, request)
+		if err != nil {
+			return err
+		}
+		if model == nil {
+			return errors.WithStack(ModelNotFoundError.Wrap("no result for parameter %s", parameter))
+		}
+
+		binder.SetParameter(parameter, model)
+	}
+
+	return nil
+}
+
+func parameterByType(model interface{}) string {
+	modelType := reflect.TypeOf(model)
+	for modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+
+	return snakeCase(modelType.Name())
+}
+
+// snakeCase converts a type name to snake case. A run of capitals is one
+// word, so UserID becomes user_id and HTTPRequest becomes http_request.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var result strings.Builder
+	for i, char := range runes {
+		if unicode.IsUpper(char) && i > 0 {
+			previousLower := !unicode.IsUpper(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if previousLower || nextLower {
+				result.WriteRune('_')
+			}
+		}
+		result.WriteRune(unicode.ToLower(char))
+	}
+
+	return result.String()
+}
@@ -0,0 +1,196 @@
+package routing
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultPathPattern = "[^/]+"
+	defaultHostPattern = "[^.]+"
+)
+
+// compiledRoute holds the precompiled regular expressions of a route. The
+// templates are compiled the same way as gorilla/mux does, so that the
+// templates of URL generation and route matching stay compatible.
+type compiledRoute struct {
+	route inter.Route
+	// The position in the collection. A lower index takes precedence.
+	index int
+	// The literal part of the path before the first parameter
+	staticPrefix string
+	path         *templateRegexp
+	host         *templateRegexp
+	err          error
+}
+
+type templateRegexp struct {
+	regexp *regexp.Regexp
+	// The names of the parameters in order of the regexp groups
+	variables []string
+	// Ignore the port of the host if the template contains no port
+	wildcardPort bool
+}
+
+func compileRoute(route inter.Route, index int) *compiledRoute {
+	result := &compiledRoute{route: route, index: index}
+	template := pathTemplate(route)
+
+	result.path, result.err = compileTemplate(template, defaultPathPattern)
+	if result.err != nil {
+		return result
+	}
+	result.staticPrefix = staticPrefix(template)
+
+	if route.Domain() != "" {
+		result.host, result.err = compileTemplate(route.Domain(), defaultHostPattern)
+		if result.err == nil && !strings.Contains(route.Domain(), ":") {
+			result.host.wildcardPort = true
+		}
+	}
+
+	return result
+}
+
+// routeVars contains the parameters of a matched route
+type routeVars struct {
+	// The parameters of the domain and the path
+	all map[string]string
+	// Only the parameters of the domain
+	domain map[string]string
+}
+
+// match returns the parameters of the route if the request matches the route
+func (r compiledRoute) match(source *http.Request) (routeVars, bool) {
+	vars := routeVars{all: map[string]string{}, domain: map[string]string{}}
+	if r.host != nil {
+		host := hostOfRequest(source)
+		if r.host.wildcardPort {
+			if i := strings.Index(host, ":"); i != -1 {
+				host = host[:i]
+			}
+		}
+		if !r.host.extract(host, vars.domain) {
+			return routeVars{}, false
+		}
+		for name, value := range vars.domain {
+			vars.all[name] = value
+		}
+	}
+
+	if !r.path.extract(source.URL.Path, vars.all) {
+		return routeVars{}, false
+	}
+
+	return vars, true
+}
+
+func (t templateRegexp) extract(value string, vars map[string]string) bool {
+	matches := t.regexp.FindStringSubmatch(value)
+	if matches == nil {
+		return false
+	}
+	for i, name := range t.variables {
+		vars[name] = matches[i+1]
+	}
+
+	return true
+}
+
+// The prefixes are applied as mux applies PathPrefix followed by Path
+func pathTemplate(route inter.Route) string {
+	template := ""
+	for _, part := range append(route.RouteOptions().Prefixes(), route.Uri()) {
+		template = strings.TrimRight(template, "/") + part
+	}
+
+	return template
+}
+
+func staticPrefix(template string) string {
+	if i := strings.Index(template, "{"); i != -1 {
+		return template[:i]
+	}
+	return template
+}
+
+func compileTemplate(template string, defaultPattern string) (*templateRegexp, error) {
+	indices, err := braceIndices(template)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &templateRegexp{}
+	pattern := bytes.NewBufferString("^")
+	end := 0
+	for i := 0; i < len(indices); i += 2 {
+		raw := template[end:indices[i]]
+		end = indices[i+1]
+		parts := strings.SplitN(template[indices[i]+1:end-1], ":", 2)
+		name := parts[0]
+		patt := defaultPattern
+		if len(parts) == 2 {
+			patt = parts[1]
+		}
+		if name == "" || patt == "" {
+			return nil, errors.New("mux: missing name or pattern in %q", template[indices[i]:end])
+		}
+		_, _ = fmt.Fprintf(pattern, "%s(?P<v%s>%s)", regexp.QuoteMeta(raw), strconv.Itoa(i/2), patt)
+		result.variables = append(result.variables, name)
+	}
+	pattern.WriteString(regexp.QuoteMeta(template[end:]))
+	pattern.WriteByte('$')
+
+	result.regexp, err = regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if result.regexp.NumSubexp() != len(result.variables) {
+		return nil, errors.New(
+			"route %s contains capture groups in its regexp. Only non-capturing groups are accepted: e.g. (?:pattern) instead of (pattern)",
+			template,
+		)
+	}
+
+	return result, nil
+}
+
+// braceIndices returns the first level curly brace indices from a string.
+// It returns an error in case of unbalanced braces.
+func braceIndices(template string) ([]int, error) {
+	var level, index int
+	var indices []int
+	for i := 0; i < len(template); i++ {
+		switch template[i] {
+		case '{':
+			if level++; level == 1 {
+				index = i
+			}
+		case '}':
+			if level--; level == 0 {
+				indices = append(indices, index, i+1)
+			} else if level < 0 {
+				return nil, errors.New("mux: unbalanced braces in %q", template)
+			}
+		}
+	}
+	if level != 0 {
+		return nil, errors.New("mux: unbalanced braces in %q", template)
+	}
+
+	return indices, nil
+}
+
+func hostOfRequest(source *http.Request) string {
+	if source.URL.IsAbs() {
+		return source.URL.Host
+	}
+	return source.Host
+}
@@ -0,0 +1,56 @@
+package routing
+
+// Documentation describes a route for generated API documentation (e.g. the
+// OpenAPI document of the openapi:generate command).
+type Documentation struct {
+	Summary string
+	Tags    []string
+	// An instance of the type of the request body
+	Request interface{}
+	// Instances of the types of the response bodies by status code
+	Responses map[int]interface{}
+}
+
+func (r Route) Documentation() Documentation {
+	return r.documentation
+}
+
+// Describe adds a summary and tags to the routes
+func (c *RouteCollection) Describe(summary string, tags ...string) *RouteCollection {
+	c.document(func(documentation *Documentation) {
+		documentation.Summary = summary
+		documentation.Tags = append(documentation.Tags, tags...)
+	})
+
+	return c
+}
+
+// Accepts registers the type of the request body (e.g. CreateUser{})
+func (c *RouteCollection) Accepts(request interface{}) *RouteCollection {
+	c.document(func(documentation *Documentation) {
+		documentation.Request = request
+	})
+
+	return c
+}
+
+// Returns registers the type of the response body by status code. Use
+// nil for a response without a body.
+func (c *RouteCollection) Returns(status int, response interface{}) *RouteCollection {
+	c.document(func(documentation *Documentation) {
+		if documentation.Responses == nil {
+			documentation.Responses = map[int]interface{}{}
+		}
+		documentation.Responses[status] = response
+	})
+
+	return c
+}
+
+func (c *RouteCollection) document(change func(documentation *Documentation)) {
+	for _, route := range c.routes {
+		if documented, ok := route.(*Route); ok {
+			change(&documented.documentation)
+		}
+	}
+}
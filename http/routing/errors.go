@@ -10,3 +10,13 @@ var RouteError = errors.New("").Level(log_level.DEBUG)
 var MethodNotAllowedError = RouteError.Wrap("HTTP method not allowed").Status(net.StatusMethodNotAllowed)
 var RouteNotFoundError = RouteError.Wrap("no match was found for the specified URL").Status(net.StatusNotFound)
 var AppNotFoundError = RouteError.Wrap("inter.App not found in RouteCollection").Status(net.StatusInternalServerError).Level(log_level.CRITICAL)
+var ModelNotFoundError = RouteNotFoundError.Wrap("model not found")
+
+var InvalidRouteError = errors.New("invalid route").Level(log_level.ERROR)
+var InvalidConstraintError = InvalidRouteError.Wrap("invalid constraint")
+var DuplicateRouteError = InvalidRouteError.Wrap("duplicate route")
+var DuplicateNameError = InvalidRouteError.Wrap("duplicate route name")
+var UnreachableRouteError = InvalidRouteError.Wrap("unreachable route")
+
+var RouteCacheError = errors.New("route cache can't be used")
+var StaleRouteCacheError = RouteCacheError.Wrap("the routes have changed, run route:cache again")
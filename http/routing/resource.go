@@ -0,0 +1,180 @@
+package routing
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/http/method"
+	"strings"
+)
+
+// The actions of a resource controller. A controller only needs to implement
+// the interfaces of the actions it supports. The routes of the other actions
+// are not registered.
+type (
+	ResourceIndex interface {
+		Index(request inter.Request) inter.Response
+	}
+	ResourceCreate interface {
+		Create(request inter.Request) inter.Response
+	}
+	ResourceStore interface {
+		Store(request inter.Request) inter.Response
+	}
+	ResourceShow interface {
+		Show(request inter.Request) inter.Response
+	}
+	ResourceEdit interface {
+		Edit(request inter.Request) inter.Response
+	}
+	ResourceUpdate interface {
+		Update(request inter.Request) inter.Response
+	}
+	ResourceDestroy interface {
+		Destroy(request inter.Request) inter.Response
+	}
+)
+
+type ResourceRoutes struct {
+	*RouteCollection
+	// The action of each route
+	actions map[inter.Route]string
+}
+
+type resourceAction struct {
+	name    string
+	methods []string
+	uri     string
+	// Whether the action is about a single item
+	item       bool
+	controller func(controller interface{}) (inter.Controller, bool)
+}
+
+// The order matters, create must be registered before show
+var resourceActions = []resourceAction{
+	{name: "index", methods: []string{method.Get, method.Head}, controller: func(c interface{}) (inter.Controller, bool) {
+		action, ok := c.(ResourceIndex)
+		return methodController(ok, func() inter.Controller { return action.Index })
+	}},
+	{name: "create", methods: []string{method.Get, method.Head}, uri: "/create", controller: func(c interface{}) (inter.Controller, bool) {
+		action, ok := c.(ResourceCreate)
+		return methodController(ok, func() inter.Controller { return action.Create })
+	}},
+	{name: "store", methods: []string{method.Post}, controller: func(c interface{}) (inter.Controller, bool) {
+		action, ok := c.(ResourceStore)
+		return methodController(ok, func() inter.Controller { return action.Store })
+	}},
+	{name: "show", methods: []string{method.Get, method.Head}, item: true, controller: func(c interface{}) (inter.Controller, bool) {
+		action, ok := c.(ResourceShow)
+		return methodController(ok, func() inter.Controller { return action.Show })
+	}},
+	{name: "edit", methods: []string{method.Get, method.Head}, uri: "/edit", item: true, controller: func(c interface{}) (inter.Controller, bool) {
+		action, ok := c.(ResourceEdit)
+		return methodController(ok, func() inter.Controller { return action.Edit })
+	}},
+	{name: "update", methods: []string{method.Put, method.Patch}, item: true, controller: func(c interface{}) (inter.Controller, bool) {
+		action, ok := c.(ResourceUpdate)
+		return methodController(ok, func() inter.Controller { return action.Update })
+	}},
+	{name: "destroy", methods: []string{method.Delete}, item: true, controller: func(c interface{}) (inter.Controller, bool) {
+		action, ok := c.(ResourceDestroy)
+		return methodController(ok, func() inter.Controller { return action.Destroy })
+	}},
+}
+
+// Resource registers the index, create, store, show, edit, update and destroy
+// routes for the actions the controller implements. Use a dot to register
+// a nested resource: "photos.comments" registers /photos/{photo}/comments.
+// The routes are named after the resource and the action (e.g. photos.index).
+func Resource(name string, controller interface{}) *ResourceRoutes {
+	return registerResource(name, controller, []string{})
+}
+
+// ApiResource registers the resource routes without create and edit
+func ApiResource(name string, controller interface{}) *ResourceRoutes {
+	return registerResource(name, controller, []string{"create", "edit"})
+}
+
+// Only keeps the routes of the given actions
+func (r *ResourceRoutes) Only(actions ...string) *ResourceRoutes {
+	r.remove(func(route inter.Route) bool {
+		return !containsString(actions, r.actions[route])
+	})
+
+	return r
+}
+
+// Except removes the routes of the given actions
+func (r *ResourceRoutes) Except(actions ...string) *ResourceRoutes {
+	r.remove(func(route inter.Route) bool {
+		return containsString(actions, r.actions[route])
+	})
+
+	return r
+}
+
+func registerResource(name string, controller interface{}, except []string) *ResourceRoutes {
+	result := &ResourceRoutes{RouteCollection: NewRouteCollection(), actions: map[inter.Route]string{}}
+	collectionUri, itemUri := resourceUris(name)
+
+	for _, action := range resourceActions {
+		actionController, ok := action.controller(controller)
+		if !ok || containsString(except, action.name) {
+			continue
+		}
+
+		uri := collectionUri
+		if action.item {
+			uri = itemUri
+		}
+
+		routes := createRoutes(action.methods, uri+action.uri, actionController)
+		routes.Name(name + "." + action.name)
+		for _, route := range routes.All() {
+			result.actions[route] = action.name
+		}
+		result.Merge(routes)
+	}
+
+	return result
+}
+
+// resourceUris returns the uri of the collection and the uri of a single item
+func resourceUris(name string) (string, string) {
+	var uri string
+	segments := strings.Split(name, ".")
+	for _, parent := range segments[:len(segments)-1] {
+		uri += "/" + parent + "/{" + singular(parent) + "}"
+	}
+
+	resource := segments[len(segments)-1]
+	uri += "/" + resource
+
+	return uri, uri + "/{" + singular(resource) + "}"
+}
+
+// singular converts the name of the resource to the name of the parameter
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss"):
+		return strings.TrimSuffix(name, "s")
+	}
+
+	return name
+}
+
+func methodController(ok bool, controller func() inter.Controller) (inter.Controller, bool) {
+	if !ok {
+		return nil, false
+	}
+	return controller(), true
+}
+
+func containsString(items []string, search string) bool {
+	for _, item := range items {
+		if item == search {
+			return true
+		}
+	}
+	return false
+}
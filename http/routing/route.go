@@ -2,6 +2,7 @@ package routing
 
 import (
 	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/http/middleware"
 	"github.com/confetti-framework/support"
 	"strings"
 )
@@ -13,6 +14,8 @@ type Route struct {
 	controller   inter.Controller
 	routeOptions RouteOptions
 	middlewares  []inter.HttpMiddleware
+	// Describes the route for generated API documentation
+	documentation Documentation
 }
 
 func NewRoute(url string, method string, controller inter.Controller) inter.Route {
@@ -121,10 +124,10 @@ func (r *Route) SetMiddleware(middlewaresToStore []inter.HttpMiddleware) inter.R
 
 	// validate and sort the middlewares
 	var validMiddlewares []inter.HttpMiddleware
-	for _, middleware := range middlewaresToStore {
-		if !excluded.Contains(support.Name(middleware)) {
+	for _, item := range middlewaresToStore {
+		if !excluded.Contains(middleware.Name(item)) {
 			// put the middleware first in the slice
-			validMiddlewares = append(validMiddlewares, middleware)
+			validMiddlewares = append(validMiddlewares, item)
 		}
 	}
 
@@ -139,6 +142,12 @@ func (r *Route) SetExcludeMiddleware(middlewares []inter.HttpMiddleware) inter.R
 	return r
 }
 
+// ExcludedMiddleware returns the middlewares that are not applied to this
+// route, also if they are part of a middleware group.
+func (r Route) ExcludedMiddleware() []inter.HttpMiddleware {
+	return r.routeOptions.excludeMiddlewares
+}
+
 type RouteOptions struct {
 	prefixes           []string
 	destination        string
@@ -159,8 +168,8 @@ func (r RouteOptions) Status() int {
 func getRouteNames(middlewares []inter.HttpMiddleware) support.Collection {
 	names := support.NewCollection()
 
-	for _, middleware := range middlewares {
-		names = names.Push(support.Name(middleware))
+	for _, item := range middlewares {
+		names = names.Push(middleware.Name(item))
 	}
 
 	return names
@@ -0,0 +1,195 @@
+package routing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// DefaultCachePath is the file used by route:cache and route:clear
+const DefaultCachePath = "storage/framework/routes.json"
+
+const routeCacheVersion = 3
+
+// routeCache contains the decorated and compiled routes. Controllers and
+// middlewares can't be serialized. They are taken from the registered routes
+// in the same order. The cache is only used when the registered routes have
+// the same Registration as the routes the cache is written from.
+type routeCache struct {
+	Version      int           `json:"version"`
+	Registration string        `json:"registration"`
+	Routes       []cachedRoute `json:"routes"`
+}
+
+type cachedRoute struct {
+	Method       string          `json:"method"`
+	Controller   string          `json:"controller"`
+	Name         string          `json:"name,omitempty"`
+	Uri          string          `json:"uri"`
+	Domain       string          `json:"domain,omitempty"`
+	StaticPrefix string          `json:"static_prefix"`
+	Path         cachedTemplate  `json:"path"`
+	Host         *cachedTemplate `json:"host,omitempty"`
+}
+
+type cachedTemplate struct {
+	Pattern      string   `json:"pattern"`
+	Variables    []string `json:"variables,omitempty"`
+	WildcardPort bool     `json:"wildcard_port,omitempty"`
+}
+
+// ControllerName returns the name of the function of the controller
+func ControllerName(controller inter.Controller) string {
+	if controller == nil {
+		return ""
+	}
+	name := runtime.FuncForPC(reflect.ValueOf(controller).Pointer()).Name()
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+// WriteCache compiles the routes and writes them to the file. Routes
+// with problems (see Validate) can't be cached.
+func (c *RouteCollection) WriteCache(path string) error {
+	if problems := c.Compile().Validate(); len(problems) > 0 {
+		return errors.WithStack(RouteCacheError.Wrap("%s", problems[0]))
+	}
+
+	cache := routeCache{Version: routeCacheVersion, Registration: c.registration}
+	for _, compiled := range sortCompiledRoutes(c.compiledRoutes().all.all()) {
+		route := compiled.route
+		cached := cachedRoute{
+			Method:       route.Method(),
+			Controller:   ControllerName(route.Controller()),
+			Name:         route.Name(),
+			Uri:          route.Uri(),
+			Domain:       route.Domain(),
+			StaticPrefix: compiled.staticPrefix,
+			Path:         newCachedTemplate(compiled.path),
+		}
+		if compiled.host != nil {
+			host := newCachedTemplate(compiled.host)
+			cached.Host = &host
+		}
+		cache.Routes = append(cache.Routes, cached)
+	}
+
+	content, err := json.Marshal(cache)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(ioutil.WriteFile(path, content, 0644))
+}
+
+// LoadCache uses the routes from the file written by WriteCache, so the
+// routes don't have to be decorated, compiled and validated again. The cache
+// is rejected if the Registration of the routes differs, then the routes are
+// left unchanged.
+func (c *RouteCollection) LoadCache(path string) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.WithStack(RouteCacheError.Wrap("%s", err))
+	}
+
+	var cache routeCache
+	if err = json.Unmarshal(content, &cache); err != nil {
+		return errors.WithStack(RouteCacheError.Wrap("%s", err))
+	}
+	registration := c.registrationHash()
+	if cache.Version != routeCacheVersion || cache.Registration != registration || len(cache.Routes) != len(c.routes) {
+		return errors.WithStack(StaleRouteCacheError)
+	}
+
+	var compiledRoutes []*compiledRoute
+	for index, route := range c.routes {
+		cached := cache.Routes[index]
+
+		compiled := &compiledRoute{route: route, index: index, staticPrefix: cached.StaticPrefix}
+		if compiled.path, err = cached.Path.compile(); err != nil {
+			return errors.WithStack(RouteCacheError.Wrap("%s", err))
+		}
+		if cached.Host != nil {
+			if compiled.host, err = cached.Host.compile(); err != nil {
+				return errors.WithStack(RouteCacheError.Wrap("%s", err))
+			}
+		}
+		compiledRoutes = append(compiledRoutes, compiled)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// Change the routes only when the whole cache is valid
+	for index, route := range c.routes {
+		route.SetUri(cache.Routes[index].Uri)
+		route.SetDomain(cache.Routes[index].Domain)
+	}
+	c.decorators = []inter.RouteDecorator{}
+	c.registration = registration
+	c.compiled = newCompiledRoutes(compiledRoutes)
+
+	return nil
+}
+
+// registrationHash returns a hash of the routes as registered, before they are
+// decorated: the method, name, controller, uri, prefixes, domain and
+// constraints of every route and the decorators of the collection.
+func (c *RouteCollection) registrationHash() string {
+	hash := sha256.New()
+	for _, decorator := range c.decorators {
+		fmt.Fprintf(hash, "decorator %T\n", decorator)
+	}
+	for _, route := range c.routes {
+		constraints := route.Constraint()
+		parameters := make([]string, 0, len(constraints))
+		for parameter := range constraints {
+			parameters = append(parameters, parameter)
+		}
+		sort.Strings(parameters)
+
+		fmt.Fprintf(hash, "route %q %q %q %q %q %q\n",
+			route.Method(),
+			route.Name(),
+			ControllerName(route.Controller()),
+			route.Uri(),
+			route.RouteOptions().Prefixes(),
+			route.Domain(),
+		)
+		for _, parameter := range parameters {
+			fmt.Fprintf(hash, "constraint %q %q\n", parameter, constraints[parameter])
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func newCachedTemplate(template *templateRegexp) cachedTemplate {
+	return cachedTemplate{
+		Pattern:      template.regexp.String(),
+		Variables:    template.variables,
+		WildcardPort: template.wildcardPort,
+	}
+}
+
+func (t cachedTemplate) compile() (*templateRegexp, error) {
+	compiled, err := regexp.Compile(t.Pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return &templateRegexp{regexp: compiled, variables: t.Variables, wildcardPort: t.WildcardPort}, nil
+}
@@ -4,9 +4,8 @@ import (
 	"github.com/confetti-framework/contract/inter"
 	"github.com/confetti-framework/errors"
 	"github.com/confetti-framework/foundation/decorator/route_decorator"
-	"github.com/confetti-framework/foundation/http/http_helper"
 	"github.com/confetti-framework/foundation/http/outcome"
-	"github.com/gorilla/mux"
+	"sync"
 )
 
 type RouteCollection struct {
@@ -14,6 +13,12 @@ type RouteCollection struct {
 	routesMapRoutes inter.MapMethodRoutes
 	routes          []inter.Route
 	decorators      []inter.RouteDecorator
+	resolvers       map[string]Resolver
+	// The decorated routes compiled into radix trees
+	compiled *compiledRoutes
+	// The Registration of the routes before they were decorated
+	registration string
+	lock         sync.Mutex
 }
 
 func NewRouteCollection(routeCollections ...inter.RouteCollection) *RouteCollection {
@@ -33,6 +38,15 @@ func Group(routeCollections ...inter.RouteCollection) *RouteCollection {
 	return NewRouteCollection(routeCollections...)
 }
 
+// Domain groups routes by a domain. The domain can contain parameters
+// (e.g. "{tenant}.example.com") that can be constrained with Where.
+func Domain(domain string, routeCollections ...inter.RouteCollection) *RouteCollection {
+	collection := NewRouteCollection(routeCollections...)
+	collection.Domain(domain)
+
+	return collection
+}
+
 func DecorateRoutes(routes *RouteCollection) {
 	for _, route := range routes.All() {
 		route_decorator.Decorate(route, routes.decorators)
@@ -49,6 +63,7 @@ func (c *RouteCollection) Push(route inter.Route) inter.RouteCollection {
 	c.routesMapRoutes[route.Method()] = append(routesByMethod, route)
 
 	c.routes = append(c.routes, route)
+	c.compiled = nil
 
 	return c
 }
@@ -58,28 +73,37 @@ func (c *RouteCollection) Merge(routeCollection inter.RouteCollection) inter.Rou
 		c.Push(route)
 	}
 
+	if collection, ok := routeCollection.(*RouteCollection); ok {
+		for parameter, resolver := range collection.resolvers {
+			c.Bind(parameter, resolver)
+		}
+	}
+
 	return c
 }
 
-func (c RouteCollection) All() []inter.Route {
+func (c *RouteCollection) All() []inter.Route {
 	return c.routes
 }
 
-func (c RouteCollection) Match(request inter.Request) inter.Route {
-	// Normally, the routes are already decorated. In that case, no decorators
-	// are present.
-	DecorateRoutes(&c)
+// Compile decorates the routes and compiles them into radix trees. This
+// happens once on the first match, but can be called at boot time to keep
+// the first request fast.
+func (c *RouteCollection) Compile() *RouteCollection {
+	c.compiledRoutes()
+	return c
+}
+
+func (c *RouteCollection) Match(request inter.Request) inter.Route {
 	routes, _ := request.App().MakeE("routes")
 	if routes == nil {
-		request.App().Singleton("routes", &c)
+		request.App().Singleton("routes", c)
 	}
 
-	routesWithUrl := c.getByMethod(request.Method())
-
 	// First, we will see if we can find a matching route for this current request
 	// method. If we can, great, we can just return it so that it can be called
 	// by the consumer. Otherwise we will check for routes with another verb.
-	route, found := c.matchAgainstRoutes(routesWithUrl, request)
+	route, found := c.matchAgainstRoutes(request)
 
 	if found {
 		return route
@@ -102,6 +126,7 @@ func (c *RouteCollection) Where(parameter, regex string) inter.RouteCollection {
 	for _, route := range c.routes {
 		route.SetConstraint(parameter, regex)
 	}
+	c.compiled = nil
 
 	return c
 }
@@ -118,6 +143,7 @@ func (c *RouteCollection) Domain(domain string) inter.RouteCollection {
 	for _, route := range c.routes {
 		route.SetDomain(domain)
 	}
+	c.compiled = nil
 
 	return c
 }
@@ -126,6 +152,7 @@ func (c *RouteCollection) Prefix(prefix string) inter.RouteCollection {
 	for _, route := range c.routes {
 		route.SetPrefix(prefix)
 	}
+	c.compiled = nil
 
 	return c
 }
@@ -172,47 +199,75 @@ func (c *RouteCollection) setStatus(status int) *RouteCollection {
 	return c
 }
 
-func (c RouteCollection) getByMethod(method string) []inter.Route {
-	return c.routesMapRoutes[method]
+func (c *RouteCollection) matchAgainstRoutes(request inter.Request) (inter.Route, bool) {
+	source := request.Source()
+
+	compiled, vars, ok := c.compiledRoutes().match(request.Method(), &source)
+	if !ok {
+		var nil inter.Route
+		return nil, false
+	}
+	if compiled.err != nil {
+		return getErrorRoute(compiled.err), true
+	}
+
+	route := compiled.route
+	request.SetUrlValues(vars.all)
+	if domainRequest, ok := request.(interface {
+		SetDomainValues(vars map[string]string) inter.Request
+	}); ok {
+		domainRequest.SetDomainValues(vars.domain)
+	}
+	if request.App() == nil {
+		return getErrorRoute(AppNotFoundError), true
+	}
+	request.App().Singleton("route", route)
+
+	// The parameters are resolved by middleware.SubstituteBindings, after
+	// the request is authenticated
+	if len(c.resolvers) > 0 {
+		request.App().Bind("route_bindings", func(request inter.Request) error {
+			return c.resolveParameters(request, vars.all)
+		})
+	}
+
+	return route, true
 }
 
-func (c *RouteCollection) matchAgainstRoutes(routes []inter.Route, request inter.Request) (inter.Route, bool) {
+func (c *RouteCollection) hasAlternateMethod(request inter.Request) bool {
 	source := request.Source()
-	var match mux.RouteMatch
-
-	for _, route := range routes {
-		muxRoute := http_helper.MuxFromRoute(route)
-		if err := muxRoute.GetError(); err != nil {
-			return getErrorRoute(err), true
-		}
-		ok := muxRoute.Match(&source, &match)
+	return c.compiledRoutes().matchAnyMethod(&source)
+}
 
-		if ok {
-			request.SetUrlValues(match.Vars)
-			if request.App() == nil {
-				return getErrorRoute(AppNotFoundError), true
-			}
-			request.App().Singleton("route", route)
+// Decorate and compile the routes only once. The decorators are kept, so
+// routes pushed later are decorated as well.
+func (c *RouteCollection) compiledRoutes() *compiledRoutes {
+	c.lock.Lock()
+	defer c.lock.Unlock()
 
-			return route, true
+	if c.compiled == nil {
+		c.registration = c.registrationHash()
+		for _, route := range c.routes {
+			route_decorator.Decorate(route, c.decorators)
 		}
+		c.compiled = compileRoutes(c.routes)
 	}
 
-	var nil inter.Route
-	return nil, false
+	return c.compiled
 }
 
-func (c RouteCollection) hasAlternateMethod(request inter.Request) bool {
-	for _, route := range c.routes {
-		var match mux.RouteMatch
-		source := request.Source()
-		ok := http_helper.MuxFromRoute(route).Match(&source, &match)
-		if ok {
-			return true
+// remove the routes that match the callback
+func (c *RouteCollection) remove(match func(route inter.Route) bool) {
+	routes := c.routes
+	c.routes = nil
+	c.routesMapRoutes = nil
+
+	for _, route := range routes {
+		if !match(route) {
+			c.Push(route)
 		}
 	}
-
-	return false
+	c.compiled = nil
 }
 
 func flatten(collections []inter.RouteCollection) inter.RouteCollection {
@@ -0,0 +1,180 @@
+package routing
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"net/http"
+	"sort"
+)
+
+// compiledRoutes contains the routes compiled into radix trees. The trees
+// are keyed by the static prefix of the path, so only the routes of which
+// the static prefix matches the path have to be matched by regexp.
+type compiledRoutes struct {
+	byMethod map[string]*routeTree
+	all      *routeTree
+}
+
+type routeTree struct {
+	root *routeNode
+}
+
+type routeNode struct {
+	prefix   string
+	children []*routeNode
+	routes   []*compiledRoute
+}
+
+func compileRoutes(routes []inter.Route) *compiledRoutes {
+	var compiled []*compiledRoute
+	for index, route := range routes {
+		compiled = append(compiled, compileRoute(route, index))
+	}
+
+	return newCompiledRoutes(compiled)
+}
+
+func newCompiledRoutes(routes []*compiledRoute) *compiledRoutes {
+	result := &compiledRoutes{byMethod: map[string]*routeTree{}, all: newRouteTree()}
+	for _, compiled := range routes {
+		method := compiled.route.Method()
+		tree, ok := result.byMethod[method]
+		if !ok {
+			tree = newRouteTree()
+			result.byMethod[method] = tree
+		}
+		tree.insert(compiled)
+		result.all.insert(compiled)
+	}
+
+	return result
+}
+
+// match finds the first registered route that matches the request. A route
+// with an invalid template that precedes the match is returned as well, so
+// the error can be shown.
+func (c compiledRoutes) match(method string, source *http.Request) (*compiledRoute, routeVars, bool) {
+	tree, ok := c.byMethod[method]
+	if !ok {
+		return nil, routeVars{}, false
+	}
+
+	for _, candidate := range tree.candidates(source.URL.Path) {
+		if candidate.err != nil {
+			return candidate, routeVars{}, true
+		}
+		if vars, ok := candidate.match(source); ok {
+			return candidate, vars, true
+		}
+	}
+
+	return nil, routeVars{}, false
+}
+
+// matchAnyMethod determines whether a route with any method matches the request
+func (c compiledRoutes) matchAnyMethod(source *http.Request) bool {
+	for _, candidate := range c.all.candidates(source.URL.Path) {
+		if candidate.err != nil {
+			continue
+		}
+		if _, ok := candidate.match(source); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newRouteTree() *routeTree {
+	return &routeTree{root: &routeNode{}}
+}
+
+func (t *routeTree) insert(route *compiledRoute) {
+	node := t.root
+	key := route.staticPrefix
+
+	for key != "" {
+		child := node.childByFirstByte(key[0])
+		if child == nil {
+			node.children = append(node.children, &routeNode{prefix: key, routes: []*compiledRoute{route}})
+			return
+		}
+
+		common := commonPrefixLength(child.prefix, key)
+		if common < len(child.prefix) {
+			// Split the node, so the common part can be shared
+			child.children = []*routeNode{{
+				prefix:   child.prefix[common:],
+				children: child.children,
+				routes:   child.routes,
+			}}
+			child.prefix = child.prefix[:common]
+			child.routes = nil
+		}
+
+		key = key[common:]
+		node = child
+	}
+
+	node.routes = append(node.routes, route)
+}
+
+// candidates returns the routes of which the static prefix matches the
+// path, sorted by the order of registration.
+func (t *routeTree) candidates(path string) []*compiledRoute {
+	node := t.root
+	result := append([]*compiledRoute{}, node.routes...)
+
+	for path != "" {
+		child := node.childByFirstByte(path[0])
+		if child == nil || len(path) < len(child.prefix) || path[:len(child.prefix)] != child.prefix {
+			break
+		}
+		result = append(result, child.routes...)
+		path = path[len(child.prefix):]
+		node = child
+	}
+
+	return sortCompiledRoutes(result)
+}
+
+// all returns all routes in the tree
+func (t *routeTree) all() []*compiledRoute {
+	var result []*compiledRoute
+	nodes := []*routeNode{t.root}
+	for len(nodes) > 0 {
+		node := nodes[0]
+		nodes = append(nodes[1:], node.children...)
+		result = append(result, node.routes...)
+	}
+
+	return result
+}
+
+func sortCompiledRoutes(routes []*compiledRoute) []*compiledRoute {
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].index < routes[j].index
+	})
+	return routes
+}
+
+func (n *routeNode) childByFirstByte(char byte) *routeNode {
+	for _, child := range n.children {
+		if child.prefix[0] == char {
+			return child
+		}
+	}
+	return nil
+}
+
+func commonPrefixLength(a, b string) int {
+	max := len(a)
+	if len(b) < max {
+		max = len(b)
+	}
+	for i := 0; i < max; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return max
+}
@@ -0,0 +1,149 @@
+package routing
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation/decorator/route_decorator"
+	"github.com/confetti-framework/foundation/http/method"
+	"strings"
+)
+
+// Validate reports duplicate routes, duplicate names, unreachable routes and
+// invalid constraints. Routes are matched in order of registration, so these
+// problems would otherwise only show up as unexpected matches.
+func (c *RouteCollection) Validate() []error {
+	all := sortCompiledRoutes(c.compiledRoutes().all.all())
+
+	var result []error
+	result = append(result, invalidTemplates(all)...)
+	result = append(result, duplicateRoutes(all)...)
+	result = append(result, duplicateNames(c.routes)...)
+	result = append(result, unreachableRoutes(all)...)
+
+	return result
+}
+
+func invalidTemplates(compiled []*compiledRoute) []error {
+	var result []error
+	for _, route := range compiled {
+		if route.err != nil {
+			result = append(result, errors.WithStack(InvalidConstraintError.Wrap(
+				"%s: %s", describeRoute(route.route), route.err,
+			)))
+		}
+	}
+
+	return result
+}
+
+func duplicateRoutes(compiled []*compiledRoute) []error {
+	var result []error
+	seen := map[string]bool{}
+	for _, route := range compiled {
+		key := describeRoute(route.route)
+		if seen[key] {
+			result = append(result, errors.WithStack(DuplicateRouteError.Wrap("%s", key)))
+		}
+		seen[key] = true
+	}
+
+	return result
+}
+
+// The same rules as outcome.RouteByName apply: HEAD routes are ignored
+func duplicateNames(routes []inter.Route) []error {
+	var result []error
+	reported := map[string]bool{}
+	// A route registered with multiple methods has one name for one URL
+	urls := map[string]map[string]bool{}
+	for _, route := range routes {
+		if route.Name() == "" || route.Method() == method.Head {
+			continue
+		}
+		if urls[route.Name()] == nil {
+			urls[route.Name()] = map[string]bool{}
+		}
+		urls[route.Name()][route.Domain()+pathTemplate(route)] = true
+		if len(urls[route.Name()]) == 2 && !reported[route.Name()] {
+			reported[route.Name()] = true
+			result = append(result, errors.WithStack(DuplicateNameError.Wrap("%s", route.Name())))
+		}
+	}
+
+	return result
+}
+
+// A route is unreachable if a route registered earlier with the same method
+// matches all URLs of the route.
+func unreachableRoutes(compiled []*compiledRoute) []error {
+	var result []error
+	for j, later := range compiled {
+		if later.err != nil {
+			continue
+		}
+		for _, earlier := range compiled[:j] {
+			if earlier.err != nil || earlier.route.Method() != later.route.Method() {
+				continue
+			}
+			if DisplayTemplate(earlier.route) == DisplayTemplate(later.route) &&
+				earlier.route.Domain() == later.route.Domain() {
+				// Already reported as duplicate route
+				continue
+			}
+			if shadows(earlier, later) {
+				result = append(result, errors.WithStack(UnreachableRouteError.Wrap(
+					"%s is shadowed by %s",
+					describeRoute(later.route),
+					describeRoute(earlier.route),
+				)))
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+func shadows(earlier *compiledRoute, later *compiledRoute) bool {
+	if earlier.route.Domain() != "" && earlier.route.Domain() != later.route.Domain() {
+		return false
+	}
+
+	// A static path can be checked by matching the path itself
+	path := DisplayTemplate(later.route)
+	if !strings.Contains(path, "{") {
+		return earlier.path.regexp.MatchString(path)
+	}
+
+	// Otherwise, only a catch-all parameter matches all paths of the route
+	if !strings.HasPrefix(later.staticPrefix, earlier.staticPrefix) {
+		return false
+	}
+	rest := pathTemplate(earlier.route)[len(earlier.staticPrefix):]
+	end := strings.Index(rest, "}")
+	if end == -1 {
+		return false
+	}
+	if suffix := (route_decorator.UriSuffixSlash{}).RevertUri(rest[end+1:]); suffix != "" {
+		return false
+	}
+
+	switch parameter := rest[:end+1]; {
+	case strings.HasSuffix(parameter, ":.*}"):
+		return true
+	case strings.HasSuffix(parameter, ":.+}"):
+		return len(later.staticPrefix) > len(earlier.staticPrefix)
+	}
+
+	return false
+}
+
+func describeRoute(route inter.Route) string {
+	return route.Method() + " " + route.Domain() + DisplayTemplate(route)
+}
+
+// DisplayTemplate returns the path template the route is matched with,
+// including the prefixes, without the placeholders of the route decorators.
+func DisplayTemplate(route inter.Route) string {
+	return route_decorator.UriSuffixSlash{}.RevertUri(pathTemplate(route))
+}
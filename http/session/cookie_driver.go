@@ -0,0 +1,58 @@
+package session
+
+import (
+	"encoding/json"
+	"github.com/confetti-framework/foundation/http/http_helper"
+	"time"
+)
+
+// CookieDriver keeps the data of the session encrypted in the cookie itself,
+// so no storage is needed. Browsers limit the size of a cookie to 4 KB.
+type CookieDriver struct {
+	// The key to encrypt the cookie with (e.g. config.App.Key)
+	Key string
+}
+
+type cookiePayload struct {
+	Id      string          `json:"id"`
+	Expires int64           `json:"expires"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func (c CookieDriver) Load(cookie string, _ time.Duration) (string, []byte, error) {
+	if cookie == "" {
+		return "", nil, nil
+	}
+
+	raw, err := http_helper.Decrypt(c.Key, cookie)
+	if err != nil {
+		// The cookie has been changed or was encrypted with another key
+		return "", nil, nil
+	}
+
+	payload := cookiePayload{}
+	if err := json.Unmarshal(raw, &payload); err != nil || time.Now().Unix() > payload.Expires {
+		return "", nil, nil
+	}
+
+	return payload.Id, payload.Data, nil
+}
+
+func (c CookieDriver) Save(id string, data []byte, lifetime time.Duration) (string, error) {
+	raw, err := json.Marshal(cookiePayload{Id: id, Expires: time.Now().Add(lifetime).Unix(), Data: data})
+	if err != nil {
+		return "", err
+	}
+
+	return http_helper.Encrypt(c.Key, raw)
+}
+
+// Destroy has nothing to remove, the cookie is replaced by the new session
+func (c CookieDriver) Destroy(_ string) error {
+	return nil
+}
+
+// Gc has nothing to remove, an expired cookie is ignored by Load
+func (c CookieDriver) Gc(_ time.Duration) error {
+	return nil
+}
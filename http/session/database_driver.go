@@ -0,0 +1,118 @@
+package session
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation/db"
+	"time"
+)
+
+// DatabaseDriver stores the sessions in a database table. Use one of the
+// connections of the DatabaseServiceProvider and create the table first:
+//
+//	CREATE TABLE sessions (
+//		id VARCHAR(40) NOT NULL PRIMARY KEY,
+//		payload TEXT NOT NULL,
+//		last_activity BIGINT NOT NULL
+//	)
+type DatabaseDriver struct {
+	Connection inter.Connection
+	// Default sessions
+	Table string
+}
+
+func NewDatabaseDriver(connection inter.Connection) DatabaseDriver {
+	return DatabaseDriver{Connection: connection, Table: "sessions"}
+}
+
+func (d DatabaseDriver) Load(cookie string, lifetime time.Duration) (string, []byte, error) {
+	if !IsValidId(cookie) {
+		return "", nil, nil
+	}
+
+	ctx, cancel := db.Context(d.Connection)
+	defer cancel()
+
+	var payload string
+	err := d.Connection.Pool().QueryRowContext(
+		ctx,
+		d.query("SELECT payload FROM %s WHERE id = ? AND last_activity >= ?"),
+		cookie, time.Now().Add(-lifetime).Unix(),
+	).Scan(&payload)
+	if err == sql.ErrNoRows {
+		return "", nil, nil
+	}
+	if err != nil {
+		return "", nil, errors.Wrap(err, "can't read session")
+	}
+
+	return cookie, []byte(payload), nil
+}
+
+func (d DatabaseDriver) Save(id string, data []byte, _ time.Duration) (string, error) {
+	ctx, cancel := db.Context(d.Connection)
+	defer cancel()
+
+	tx, err := d.Connection.Pool().BeginTx(ctx, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "can't start session transaction")
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err = tx.ExecContext(ctx, d.query("DELETE FROM %s WHERE id = ?"), id); err != nil {
+		return "", errors.Wrap(err, "can't save session")
+	}
+	_, err = tx.ExecContext(
+		ctx,
+		d.query("INSERT INTO %s (id, payload, last_activity) VALUES (?, ?, ?)"),
+		id, string(data), time.Now().Unix(),
+	)
+	if err != nil {
+		return "", errors.Wrap(err, "can't save session")
+	}
+	if err = tx.Commit(); err != nil {
+		return "", errors.Wrap(err, "can't save session")
+	}
+
+	return id, nil
+}
+
+func (d DatabaseDriver) Destroy(id string) error {
+	ctx, cancel := db.Context(d.Connection)
+	defer cancel()
+
+	_, err := d.Connection.Pool().ExecContext(ctx, d.query("DELETE FROM %s WHERE id = ?"), id)
+	if err != nil {
+		return errors.Wrap(err, "can't destroy session")
+	}
+
+	return nil
+}
+
+func (d DatabaseDriver) Gc(lifetime time.Duration) error {
+	ctx, cancel := db.Context(d.Connection)
+	defer cancel()
+
+	_, err := d.Connection.Pool().ExecContext(
+		ctx,
+		d.query("DELETE FROM %s WHERE last_activity < ?"),
+		time.Now().Add(-lifetime).Unix(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "can't remove expired sessions")
+	}
+
+	return nil
+}
+
+// query adds the table name and converts the placeholders to the syntax of the driver
+func (d DatabaseDriver) query(query string) string {
+	table := d.Table
+	if table == "" {
+		table = "sessions"
+	}
+
+	return db.Placeholders(d.Connection, fmt.Sprintf(query, table))
+}
@@ -0,0 +1,15 @@
+package session
+
+import "time"
+
+// Driver loads and saves the data of sessions
+type Driver interface {
+	// Load returns the id and the data of the session by the value of the cookie.
+	// An empty id and no error is returned if the session is unknown or expired.
+	Load(cookie string, lifetime time.Duration) (id string, data []byte, err error)
+	// Save stores the data and returns the value of the cookie
+	Save(id string, data []byte, lifetime time.Duration) (cookie string, err error)
+	Destroy(id string) error
+	// Gc removes the sessions that are expired
+	Gc(lifetime time.Duration) error
+}
@@ -0,0 +1,10 @@
+package session
+
+import (
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/syslog/log_level"
+	net "net/http"
+)
+
+var NotStartedError = errors.New("session not started, add middleware.StartSession to the route").Status(net.StatusInternalServerError).Level(log_level.ERROR)
+var KeyNotFoundError = errors.New("key not found in session").Level(log_level.DEBUG)
@@ -0,0 +1,85 @@
+package session
+
+import (
+	"github.com/confetti-framework/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileDriver stores every session in a file with the id as name
+type FileDriver struct {
+	// The directory of the session files (e.g. storage/framework/sessions)
+	Path string
+}
+
+func (f FileDriver) Load(cookie string, lifetime time.Duration) (string, []byte, error) {
+	if !IsValidId(cookie) {
+		return "", nil, nil
+	}
+
+	file := filepath.Join(f.Path, cookie)
+	info, err := os.Stat(file)
+	if os.IsNotExist(err) || (err == nil && expired(info, lifetime)) {
+		return "", nil, nil
+	}
+	if err != nil {
+		return "", nil, errors.Wrap(err, "can't read session")
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "can't read session")
+	}
+
+	return cookie, data, nil
+}
+
+func (f FileDriver) Save(id string, data []byte, _ time.Duration) (string, error) {
+	if err := os.MkdirAll(f.Path, 0700); err != nil {
+		return "", errors.Wrap(err, "can't create session directory")
+	}
+	if err := ioutil.WriteFile(filepath.Join(f.Path, id), data, 0600); err != nil {
+		return "", errors.Wrap(err, "can't save session")
+	}
+
+	return id, nil
+}
+
+func (f FileDriver) Destroy(id string) error {
+	if !IsValidId(id) {
+		return nil
+	}
+	err := os.Remove(filepath.Join(f.Path, id))
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "can't destroy session")
+	}
+
+	return nil
+}
+
+func (f FileDriver) Gc(lifetime time.Duration) error {
+	files, err := ioutil.ReadDir(f.Path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "can't read session directory")
+	}
+
+	for _, info := range files {
+		if info.IsDir() || !IsValidId(info.Name()) || !expired(info, lifetime) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(f.Path, info.Name())); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "can't remove expired session")
+		}
+	}
+
+	return nil
+}
+
+func expired(info os.FileInfo, lifetime time.Duration) bool {
+	return info.ModTime().Add(lifetime).Before(time.Now())
+}
@@ -0,0 +1,229 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/support"
+	"regexp"
+)
+
+const (
+	flashNew = "_flash.new"
+	flashOld = "_flash.old"
+	tokenKey = "_token"
+)
+
+var validId = regexp.MustCompile("^[0-9a-f]{40}$")
+
+// Session contains the data of one client between requests. The session is
+// started by middleware.StartSession and can be obtained with request.Session().
+type Session struct {
+	id         string
+	previousId string
+	data       map[string]interface{}
+}
+
+// NewSession creates a session with the id and the stored data. A new id is
+// generated if the id is invalid.
+func NewSession(id string, raw []byte) *Session {
+	session := &Session{id: id, data: map[string]interface{}{}}
+	if !IsValidId(id) {
+		session.id = NewId()
+	}
+	if len(raw) > 0 && json.Unmarshal(raw, &session.data) != nil {
+		session.data = map[string]interface{}{}
+	}
+
+	return session
+}
+
+// FromApp returns the session that is started by middleware.StartSession
+func FromApp(app inter.AppReader) (*Session, error) {
+	raw, err := app.MakeE("session")
+	if err != nil {
+		return nil, errors.WithStack(NotStartedError)
+	}
+	result, ok := raw.(*Session)
+	if !ok {
+		return nil, errors.WithStack(NotStartedError)
+	}
+
+	return result, nil
+}
+
+// NewId generates a random session id of 40 hexadecimal characters
+func NewId() string {
+	raw := make([]byte, 20)
+	if _, err := rand.Read(raw); err != nil {
+		panic(errors.Wrap(err, "can't generate session id"))
+	}
+	return hex.EncodeToString(raw)
+}
+
+// IsValidId determines whether the id can be generated by NewId
+func IsValidId(id string) bool {
+	return validId.MatchString(id)
+}
+
+func (s *Session) Id() string {
+	return s.id
+}
+
+// PreviousId returns the id before the session was regenerated
+func (s *Session) PreviousId() string {
+	return s.previousId
+}
+
+func (s *Session) Get(key string) support.Value {
+	result, err := s.GetE(key)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (s *Session) GetE(key string) (support.Value, error) {
+	value, ok := s.data[key]
+	if !ok {
+		return support.NewValue(nil), KeyNotFoundError.Wrap("key '%s'", key)
+	}
+	return support.NewValue(value), nil
+}
+
+func (s *Session) GetOr(key string, defaultValue interface{}) support.Value {
+	result, err := s.GetE(key)
+	if err != nil {
+		return support.NewValue(defaultValue)
+	}
+	return result
+}
+
+func (s *Session) Has(key string) bool {
+	_, ok := s.data[key]
+	return ok
+}
+
+// All returns the data of the session without the flash administration
+func (s *Session) All() map[string]interface{} {
+	result := map[string]interface{}{}
+	for key, value := range s.data {
+		if key != flashNew && key != flashOld {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+func (s *Session) Put(key string, value interface{}) *Session {
+	s.data[key] = value
+	return s
+}
+
+func (s *Session) Forget(keys ...string) *Session {
+	for _, key := range keys {
+		delete(s.data, key)
+	}
+	return s
+}
+
+// Flash puts a value in the session that is only available in the next request
+func (s *Session) Flash(key string, value interface{}) *Session {
+	s.Put(key, value)
+	s.data[flashNew] = appendKey(s.flashKeys(flashNew), key)
+	s.data[flashOld] = removeKey(s.flashKeys(flashOld), key)
+	return s
+}
+
+// Reflash keeps all flash data for an additional request
+func (s *Session) Reflash() *Session {
+	keys := s.flashKeys(flashNew)
+	for _, key := range s.flashKeys(flashOld) {
+		keys = appendKey(keys, key)
+	}
+	s.data[flashNew] = keys
+	s.data[flashOld] = []string{}
+	return s
+}
+
+// Flush removes all data from the session
+func (s *Session) Flush() *Session {
+	s.data = map[string]interface{}{}
+	return s
+}
+
+// Regenerate gives the session a new id to prevent session fixation. Use it
+// after the user logs in. The session with the previous id will be destroyed.
+func (s *Session) Regenerate() *Session {
+	if s.previousId == "" {
+		s.previousId = s.id
+	}
+	s.id = NewId()
+	return s
+}
+
+// Invalidate removes all data and regenerates the id (e.g. when the user logs out)
+func (s *Session) Invalidate() *Session {
+	return s.Flush().Regenerate()
+}
+
+// Token returns the CSRF token of the session. The token is generated on first use.
+func (s *Session) Token() string {
+	if token, ok := s.data[tokenKey].(string); ok && token != "" {
+		return token
+	}
+	return s.RegenerateToken()
+}
+
+// RegenerateToken replaces the CSRF token with a new token
+func (s *Session) RegenerateToken() string {
+	token := NewId()
+	s.data[tokenKey] = token
+	return token
+}
+
+// AgeFlashData removes the flash data of the previous request. The flash data
+// of the current request will be removed in the next request.
+func (s *Session) AgeFlashData() {
+	for _, key := range s.flashKeys(flashOld) {
+		delete(s.data, key)
+	}
+	s.data[flashOld] = s.flashKeys(flashNew)
+	s.data[flashNew] = []string{}
+}
+
+// Marshal converts the data to JSON so it can be saved by a driver
+func (s *Session) Marshal() ([]byte, error) {
+	return json.Marshal(s.data)
+}
+
+func (s *Session) flashKeys(name string) []string {
+	var result []string
+	switch keys := s.data[name].(type) {
+	case []string:
+		result = append(result, keys...)
+	case []interface{}:
+		for _, key := range keys {
+			if key, ok := key.(string); ok {
+				result = append(result, key)
+			}
+		}
+	}
+	return result
+}
+
+func appendKey(keys []string, key string) []string {
+	return append(removeKey(keys, key), key)
+}
+
+func removeKey(keys []string, key string) []string {
+	result := []string{}
+	for _, item := range keys {
+		if item != key {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -7,12 +7,19 @@ import (
 	"html/template"
 )
 
+// ContentByView executes the template of the view. The functions are added
+// before the template is parsed, so the template can use them.
 func ContentByView(
 	view inter.View,
 	builder func(template *template.Template) (*template.Template, error),
+	functions ...template.FuncMap,
 ) (string, error) {
 	buf := bytes.NewBufferString("")
-	t, err := template.New("template.view").Parse(view.Template())
+	t := template.New("template.view")
+	for _, funcMap := range functions {
+		t.Funcs(funcMap)
+	}
+	t, err := t.Parse(view.Template())
 	if err != nil {
 		return "", err
 	}
@@ -30,3 +37,32 @@ func ContentByView(
 
 	return buf.String(), err
 }
+
+// TemplateFunctions returns the functions bound as "template_functions". A
+// middleware can add functions for the current request with AddTemplateFunctions.
+func TemplateFunctions(app inter.AppReader) template.FuncMap {
+	raw, err := app.MakeE("template_functions")
+	if err != nil {
+		return template.FuncMap{}
+	}
+	functions, ok := raw.(template.FuncMap)
+	if !ok {
+		return template.FuncMap{}
+	}
+
+	return functions
+}
+
+// AddTemplateFunctions binds the functions as "template_functions" without
+// removing the functions that are already bound.
+func AddTemplateFunctions(app inter.App, functions template.FuncMap) {
+	result := template.FuncMap{}
+	for name, function := range TemplateFunctions(app) {
+		result[name] = function
+	}
+	for name, function := range functions {
+		result[name] = function
+	}
+
+	app.Bind("template_functions", result)
+}
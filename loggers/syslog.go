@@ -95,7 +95,8 @@ func (r Syslog) LogWith(severity log_level.Level, message string, rawContext int
 		severity,
 		r.group,
 		structuredData,
-		message+" %s",
+		"%s %s",
+		message,
 		rawData,
 	)
 }
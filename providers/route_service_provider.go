@@ -0,0 +1,76 @@
+package providers
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/confetti-framework/foundation/loggers"
+	"os"
+	"strings"
+)
+
+type RouteServiceProvider struct {
+	Routes inter.RouteCollection
+	// The file written by route:cache. Default routing.DefaultCachePath
+	CachePath string
+}
+
+// Register binds the routes as "routes". The routes are loaded from the route
+// cache if present. Otherwise, the routes are compiled and validated once. In
+// debug mode, invalid routes stop the application. Otherwise, the problems are
+// logged as warning.
+func (r RouteServiceProvider) Register(container inter.Container) inter.Container {
+	if collection, ok := r.Routes.(*routing.RouteCollection); ok && !r.loadCache(container, collection) {
+		r.report(container, collection.Compile().Validate())
+	}
+
+	container.Singleton("routes", r.Routes)
+
+	return container
+}
+
+func (r RouteServiceProvider) loadCache(container inter.Container, collection *routing.RouteCollection) bool {
+	path := r.CachePath
+	if path == "" {
+		path = routing.DefaultCachePath
+	}
+	if _, err := os.Stat(path); err != nil {
+		return false
+	}
+
+	// A stale cache is not fatal, the routes are compiled instead
+	if err := collection.LoadCache(path); err != nil {
+		r.warn(container, []error{err})
+		return false
+	}
+
+	return true
+}
+
+func (r RouteServiceProvider) report(container inter.Container, problems []error) {
+	if len(problems) == 0 {
+		return
+	}
+
+	debug, err := container.MakeE("config.App.Debug")
+	if err == nil && debug == true {
+		var messages []string
+		for _, problem := range problems {
+			messages = append(messages, problem.Error())
+		}
+		panic(errors.WithStack(routing.InvalidRouteError.Wrap("%s", strings.Join(messages, "; "))))
+	}
+
+	r.warn(container, problems)
+}
+
+func (r RouteServiceProvider) warn(container inter.Container, problems []error) {
+	channel, err := container.MakeE("config.Logging.Default")
+	if err != nil {
+		return
+	}
+	logger := loggers.NewLoggerFacade(loggers.Stack{Channels: []string{channel.(string)}}).SetApp(container)
+	for _, problem := range problems {
+		logger.WarningWith(problem.Error(), problem)
+	}
+}
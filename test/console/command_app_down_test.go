@@ -0,0 +1,91 @@
+package console
+
+import (
+	"bytes"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/console"
+	"github.com/confetti-framework/foundation/http/maintenance"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func Test_app_down_get_name(t *testing.T) {
+	require.Equal(t, "app:down", console.AppDown{}.Name())
+	require.Equal(t, "app:up", console.AppUp{}.Name())
+}
+
+func Test_app_down_writes_marker_file(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "framework", "down")
+
+	output, code := handleMaintenance(path, "app:down", "--retry", "60", "--secret", "/1630542a", "--allow", "10.0.0.0/8, 203.0.113.9")
+
+	require.Equal(t, inter.Success, code)
+	require.Contains(t, output, "Application is now in maintenance mode.")
+	require.Contains(t, output, "Visit /1630542a to bypass maintenance mode.")
+	mode, active, err := maintenance.Active(path)
+	require.NoError(t, err)
+	require.True(t, active)
+	require.Equal(t, 60, mode.Retry)
+	require.Equal(t, "1630542a", mode.Secret)
+	require.Equal(t, []string{"10.0.0.0/8", "203.0.113.9"}, mode.Allow)
+}
+
+func Test_app_down_with_view(t *testing.T) {
+	dir := t.TempDir()
+	view := filepath.Join(dir, "down.gohtml")
+	require.NoError(t, ioutil.WriteFile(view, []byte("<h1>Back soon</h1>"), 0644))
+	path := filepath.Join(dir, "down")
+
+	_, code := handleMaintenance(path, "app:down", "--view", view, "--status", "200")
+
+	require.Equal(t, inter.Success, code)
+	mode, _, err := maintenance.Active(path)
+	require.NoError(t, err)
+	require.Equal(t, "<h1>Back soon</h1>", mode.Template)
+	require.Equal(t, 200, mode.Status)
+}
+
+func Test_app_down_with_missing_view(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "down")
+
+	output, code := handleMaintenance(path, "app:down", "--view", "missing.gohtml")
+
+	require.Equal(t, inter.Failure, code)
+	require.Contains(t, output, "View could not be read")
+}
+
+func Test_app_up_removes_marker_file(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "down")
+	handleMaintenance(path, "app:down")
+
+	output, code := handleMaintenance(path, "app:up")
+
+	require.Equal(t, inter.Success, code)
+	require.Contains(t, output, "Application is now live.")
+	_, active, err := maintenance.Active(path)
+	require.NoError(t, err)
+	require.False(t, active)
+}
+
+func handleMaintenance(path string, args ...string) (string, inter.ExitCode) {
+	writer, app := setUp()
+	var writerErr bytes.Buffer
+
+	osArgs := []interface{}{"/main"}
+	for _, arg := range args {
+		osArgs = append(osArgs, arg)
+	}
+	app.Bind("config.App.OsArgs", osArgs)
+	app.Bind("maintenance_path", path)
+
+	code := console.Kernel{
+		App:       app,
+		Writer:    &writer,
+		WriterErr: &writerErr,
+		Commands:  []inter.Command{console.AppDown{}, console.AppUp{}},
+	}.Handle()
+
+	return TrimDoubleSpaces(writer.String() + writerErr.String()), code
+}
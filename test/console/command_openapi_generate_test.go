@@ -0,0 +1,126 @@
+package console
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/console"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+type createUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+type apiUser struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func Test_openapi_generate_get_name(t *testing.T) {
+	require.Equal(t, "openapi:generate", console.OpenApiGenerate{}.Name())
+}
+
+func Test_openapi_generate_writes_document(t *testing.T) {
+	document, code := handleOpenApiGenerate(t)
+
+	require.Equal(t, inter.Success, code)
+	require.Equal(t, "3.1.0", document["openapi"])
+	require.Equal(t, map[string]interface{}{"title": "Confetti", "version": "2.0.0"}, document["info"])
+}
+
+func Test_openapi_generate_operations(t *testing.T) {
+	document, _ := handleOpenApiGenerate(t)
+	paths := document["paths"].(map[string]interface{})
+
+	require.Len(t, paths, 2)
+	users := paths["/api/users"].(map[string]interface{})
+	require.NotContains(t, users, "head")
+
+	index := users["get"].(map[string]interface{})
+	require.Equal(t, "api.users.index", index["operationId"])
+	require.Equal(t, "List the users", index["summary"])
+	require.Equal(t, []interface{}{"users"}, index["tags"])
+	require.Equal(t, map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"$ref": "#/components/schemas/apiUser"},
+	}, schemaOf(index["responses"], "200"))
+
+	store := users["post"].(map[string]interface{})
+	body := store["requestBody"].(map[string]interface{})["content"].(map[string]interface{})["application/json"]
+	require.Equal(t, map[string]interface{}{"$ref": "#/components/schemas/createUser"}, body.(map[string]interface{})["schema"])
+	require.Equal(t, "Unprocessable Entity", store["responses"].(map[string]interface{})["422"].(map[string]interface{})["description"])
+}
+
+func Test_openapi_generate_path_parameters(t *testing.T) {
+	document, _ := handleOpenApiGenerate(t)
+	show := document["paths"].(map[string]interface{})["/api/users/{id}"].(map[string]interface{})["get"].(map[string]interface{})
+
+	require.Equal(t, []interface{}{map[string]interface{}{
+		"name":     "id",
+		"in":       "path",
+		"required": true,
+		"schema":   map[string]interface{}{"type": "string", "pattern": "^[0-9]+$"},
+	}}, show["parameters"])
+	require.Equal(t, map[string]interface{}{"description": "OK"}, show["responses"].(map[string]interface{})["200"])
+}
+
+func Test_openapi_generate_components(t *testing.T) {
+	document, _ := handleOpenApiGenerate(t)
+	schemas := document["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+
+	require.Equal(t, map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name":  map[string]interface{}{"type": "string"},
+			"email": map[string]interface{}{"type": "string"},
+		},
+		"required": []interface{}{"name"},
+	}, schemas["createUser"])
+}
+
+func schemaOf(responses interface{}, status string) interface{} {
+	response := responses.(map[string]interface{})[status].(map[string]interface{})
+	return response["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"]
+}
+
+func handleOpenApiGenerate(t *testing.T) (map[string]interface{}, inter.ExitCode) {
+	writer, app := setUp()
+	var writerErr bytes.Buffer
+	output := filepath.Join(t.TempDir(), "openapi.json")
+
+	app.Bind("config.App.OsArgs", []interface{}{"/main", "openapi:generate", "--output", output, "--version", "2.0.0"})
+	app.Singleton("routes", routing.NewRouteCollection(
+		routing.Group(
+			routing.Get("/users", showUser).
+				Describe("List the users", "users").
+				Returns(200, []apiUser{}).
+				Name(".index"),
+			routing.Post("/users", showUser).
+				Accepts(createUser{}).
+				Returns(201, apiUser{}).
+				Returns(422, nil).
+				Name(".store"),
+			routing.Get("/users/{id}", showUser).Name(".show"),
+		).Prefix("/api").Name("api.users").Where("id", "[0-9]+"),
+	))
+
+	code := console.Kernel{
+		App:       app,
+		Writer:    &writer,
+		WriterErr: &writerErr,
+		Commands:  []inter.Command{console.OpenApiGenerate{}},
+	}.Handle()
+
+	var document map[string]interface{}
+	content, err := ioutil.ReadFile(output)
+	require.Nil(t, err, writerErr.String())
+	require.Nil(t, json.Unmarshal(content, &document))
+
+	return document, code
+}
@@ -0,0 +1,63 @@
+package console
+
+import (
+	"bytes"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/console"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_route_cache_get_name(t *testing.T) {
+	require.Equal(t, "route:cache", console.RouteCache{}.Name())
+	require.Equal(t, "route:clear", console.RouteClear{}.Name())
+}
+
+func Test_route_cache_writes_file(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "routes.json")
+
+	output, code := handleRouteCache("route:cache", "--path", path)
+
+	require.Equal(t, inter.Success, code)
+	require.Contains(t, output, "Routes cached successfully.")
+	require.FileExists(t, path)
+	require.Nil(t, routing.NewRouteCollection(routing.Get("/users/{id}", showUser).Name("user")).LoadCache(path))
+}
+
+func Test_route_clear_removes_file(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	handleRouteCache("route:cache", "--path", path)
+
+	output, code := handleRouteCache("route:clear", "--path", path)
+
+	require.Equal(t, inter.Success, code)
+	require.Contains(t, output, "Route cache cleared.")
+	_, err := os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+}
+
+func handleRouteCache(args ...string) (string, inter.ExitCode) {
+	writer, app := setUp()
+	var writerErr bytes.Buffer
+
+	osArgs := []interface{}{"/main"}
+	for _, arg := range args {
+		osArgs = append(osArgs, arg)
+	}
+	app.Bind("config.App.OsArgs", osArgs)
+	app.Singleton("routes", routing.NewRouteCollection(
+		routing.Get("/users/{id}", showUser).Name("user"),
+	))
+
+	code := console.Kernel{
+		App:       app,
+		Writer:    &writer,
+		WriterErr: &writerErr,
+		Commands:  []inter.Command{console.RouteCache{}, console.RouteClear{}},
+	}.Handle()
+
+	return TrimDoubleSpaces(writer.String() + writerErr.String()), code
+}
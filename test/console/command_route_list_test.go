@@ -0,0 +1,125 @@
+package console
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/console"
+	"github.com/confetti-framework/foundation/http/middleware"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_route_list_get_name(t *testing.T) {
+	require.Equal(t, "route:list", console.RouteList{}.Name())
+}
+
+func Test_route_list_shows_routes(t *testing.T) {
+	output, code := handleRouteList("route:list")
+
+	require.Equal(t, inter.Success, code)
+	require.Contains(t, output, "GET|HEAD /api/users/{id:[0-9]+} api.users.show console.showUser middleware.RequestID")
+	require.Contains(t, output, "POST /api/users api.users.store")
+	require.Contains(t, output, "GET|HEAD /docs docs.confetti-framework.com")
+}
+
+func Test_route_list_filter_by_method(t *testing.T) {
+	output, code := handleRouteList("route:list", "--method", "post")
+
+	require.Equal(t, inter.Success, code)
+	require.Contains(t, output, "/api/users")
+	require.NotContains(t, output, "/docs")
+}
+
+func Test_route_list_filter_by_name(t *testing.T) {
+	output, code := handleRouteList("route:list", "--name", "show")
+
+	require.Equal(t, inter.Success, code)
+	require.Contains(t, output, "api.users.show")
+	require.NotContains(t, output, "api.users.store")
+}
+
+func Test_route_list_filter_by_path(t *testing.T) {
+	output, code := handleRouteList("route:list", "--path", "docs")
+
+	require.Equal(t, inter.Success, code)
+	require.Contains(t, output, "/docs")
+	require.NotContains(t, output, "/api/users")
+}
+
+func Test_route_list_without_matching_routes(t *testing.T) {
+	_, code := handleRouteList("route:list", "--path", "unknown")
+
+	require.Equal(t, inter.Failure, code)
+}
+
+func Test_route_list_as_json(t *testing.T) {
+	output, code := handleRouteList("route:list", "--json", "--name", "show")
+
+	var rows []map[string]interface{}
+	require.Equal(t, inter.Success, code)
+	require.Nil(t, json.Unmarshal([]byte(output), &rows))
+	require.Len(t, rows, 1)
+	require.Equal(t, "/api/users/{id:[0-9]+}", rows[0]["uri"])
+	require.Equal(t, []interface{}{"GET", "HEAD"}, rows[0]["methods"])
+	require.Equal(t, []interface{}{"middleware.RequestID"}, rows[0]["middleware"])
+}
+
+func showUser(_ inter.Request) inter.Response {
+	return outcome.Html("user")
+}
+
+func Test_route_list_uri_as_matched(t *testing.T) {
+	routes := routing.Group(routing.Get("/users", showUser)).Prefix("/api/")
+
+	output, code := handleRouteListWith(routes, "route:list", "--json")
+
+	var rows []map[string]interface{}
+	require.Equal(t, inter.Success, code)
+	require.Nil(t, json.Unmarshal([]byte(output), &rows))
+	require.Equal(t, "/api/users", rows[0]["uri"])
+}
+
+func Test_route_list_keeps_decorators_of_routes(t *testing.T) {
+	routes := routing.NewRouteCollection(routing.Get("/users", showUser))
+	_, code := handleRouteListWith(routes, "route:list")
+	require.Equal(t, inter.Success, code)
+
+	routes.Merge(routing.Get("/posts/{id}", showUser).Where("id", "[0-9]+"))
+	routes.Compile()
+
+	require.Equal(t, "/posts/{id:[0-9]+}", routing.DisplayTemplate(routes.All()[len(routes.All())-1]))
+}
+
+func handleRouteList(args ...string) (string, inter.ExitCode) {
+	return handleRouteListWith(routing.NewRouteCollection(
+		routing.Group(
+			routing.Get("/users/{id}", showUser).Name(".show").Middleware(middleware.RequestID{}),
+			routing.Post("/users", showUser).Name(".store"),
+		).Prefix("/api").Name("api.users").Where("id", "[0-9]+"),
+		routing.Get("/docs", showUser).Domain("docs.confetti-framework.com"),
+	), args...)
+}
+
+func handleRouteListWith(routes inter.RouteCollection, args ...string) (string, inter.ExitCode) {
+	writer, app := setUp()
+	var writerErr bytes.Buffer
+
+	osArgs := []interface{}{"/main"}
+	for _, arg := range args {
+		osArgs = append(osArgs, arg)
+	}
+	app.Bind("config.App.OsArgs", osArgs)
+	app.Singleton("routes", routes)
+
+	code := console.Kernel{
+		App:       app,
+		Writer:    &writer,
+		WriterErr: &writerErr,
+		Commands:  []inter.Command{console.RouteList{}},
+	}.Handle()
+
+	return TrimDoubleSpaces(writer.String()), code
+}
@@ -0,0 +1,99 @@
+package encode
+
+import (
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation/encoder"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+	net "net/http"
+	"testing"
+)
+
+func Test_xml_to_value(t *testing.T) {
+	request := http.NewRequest(http.Options{Content: `<?xml version="1.0"?>
+<user id="12">
+	<name><first>Janet</first><last>Prichard</last></name>
+	<role>admin</role>
+	<role>author</role>
+</user>`})
+	value := encoder.RequestWithXmlToValue(request)
+
+	require.Equal(t, "12", value.Get("@id").String())
+	require.Equal(t, "Janet", value.Get("name.first").String())
+	require.Equal(t, "author", value.Get("role.1").String())
+}
+
+func Test_invalid_xml_to_value(t *testing.T) {
+	request := http.NewRequest(http.Options{Content: `<user><name>`})
+	value := encoder.RequestWithXmlToValue(request)
+
+	err := value.Raw().(error)
+	require.True(t, errors.Is(err, encoder.DecodeError))
+	status, _ := errors.FindStatus(err)
+	require.Equal(t, net.StatusBadRequest, status)
+}
+
+func Test_yaml_to_value(t *testing.T) {
+	request := http.NewRequest(http.Options{Content: "name:\n  first: Janet\nroles:\n  - admin\n  - author\nage: 47\n"})
+	value := encoder.RequestWithYamlToValue(request)
+
+	require.Equal(t, "Janet", value.Get("name.first").String())
+	require.Equal(t, "author", value.Get("roles.1").String())
+	require.Equal(t, 47, value.Get("age").Int())
+}
+
+func Test_invalid_yaml_to_value(t *testing.T) {
+	request := http.NewRequest(http.Options{Content: "name: [Janet\n"})
+	value := encoder.RequestWithYamlToValue(request)
+
+	err := value.Raw().(error)
+	require.True(t, errors.Is(err, encoder.DecodeError))
+	status, _ := errors.FindStatus(err)
+	require.Equal(t, net.StatusBadRequest, status)
+}
+
+func Test_msgpack_to_value(t *testing.T) {
+	body, err := msgpack.Marshal(map[string]interface{}{"name": map[string]string{"first": "Janet"}})
+	require.NoError(t, err)
+
+	request := http.NewRequest(http.Options{Content: string(body)})
+	value := encoder.RequestWithMsgpackToValue(request)
+
+	require.Equal(t, "Janet", value.Get("name.first").String())
+}
+
+func Test_invalid_msgpack_to_value(t *testing.T) {
+	// A map of 2 entries with only one key
+	request := http.NewRequest(http.Options{Content: "\x82\xa4name"})
+	value := encoder.RequestWithMsgpackToValue(request)
+
+	err := value.Raw().(error)
+	require.True(t, errors.Is(err, encoder.DecodeError))
+	status, _ := errors.FindStatus(err)
+	require.Equal(t, net.StatusBadRequest, status)
+}
+
+func Test_ndjson_to_value(t *testing.T) {
+	request := http.NewRequest(http.Options{Content: "{\"name\":\"Janet\"}\n\n{\"name\":\"Bob\"}\n"})
+	value := encoder.RequestWithNdjsonToValue(request)
+
+	require.Len(t, value.Collection(), 2)
+	require.Equal(t, "Bob", value.Get("1.name").String())
+}
+
+func Test_invalid_ndjson_line_to_value(t *testing.T) {
+	request := http.NewRequest(http.Options{Content: "{\"name\":\"Janet\"}\n\n{\"name\":\n"})
+	value := encoder.RequestWithNdjsonToValue(request)
+
+	err := value.Raw().(error)
+	require.True(t, errors.Is(err, encoder.DecodeError))
+	require.EqualError(t, err, "invalid JSON on line 3: can't decode request body")
+}
+
+func Test_text_to_value(t *testing.T) {
+	request := http.NewRequest(http.Options{Content: "plain text"})
+	value := encoder.RequestWithTextToValue(request)
+
+	require.Equal(t, "plain text", value.String())
+}
@@ -0,0 +1,176 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/middleware"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/confetti-framework/foundation/loggers"
+	"github.com/confetti-framework/foundation/test/mock"
+	"github.com/confetti-framework/syslog/log_level"
+	"github.com/stretchr/testify/require"
+	net "net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func Test_access_log_combined_format(t *testing.T) {
+	output := &bytes.Buffer{}
+	request := accessLogRequest(output, "/users?page=2")
+
+	accessLog(middleware.AccessLog{}, request, dummyMiddlewareResponder)
+
+	require.Regexp(
+		t,
+		`^203\.0\.113\.9 - - \[.+\] "GET /users\?page=2 HTTP/1\.1" 200 11 "https://example\.com/" "test-agent"`,
+		logMessage(output.String()),
+	)
+}
+
+func Test_access_log_common_format(t *testing.T) {
+	output := &bytes.Buffer{}
+	request := accessLogRequest(output, "/users")
+
+	accessLog(middleware.AccessLog{Format: middleware.AccessLogCommon}, request, dummyMiddlewareResponder)
+
+	require.Regexp(t, `^203\.0\.113\.9 - - \[.+\] "GET /users HTTP/1\.1" 200 11 \n$`, logMessage(output.String()))
+}
+
+func Test_access_log_json_format(t *testing.T) {
+	output := &bytes.Buffer{}
+	request := accessLogRequest(output, "/users")
+
+	accessLog(middleware.AccessLog{Format: middleware.AccessLogJson}, request, func(request inter.Request) inter.Response {
+		return outcome.Html("").Status(net.StatusNoContent)
+	})
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimSpace(logMessage(output.String()))), &entry))
+	require.Equal(t, "GET", entry["method"])
+	require.Equal(t, "/users", entry["path"])
+	require.Equal(t, float64(net.StatusNoContent), entry["status"])
+	require.Equal(t, "203.0.113.9", entry["ip"])
+	require.Equal(t, "test-agent", entry["user_agent"])
+}
+
+func Test_access_log_structured_data(t *testing.T) {
+	output := &bytes.Buffer{}
+	request := accessLogRequest(output, "/users")
+	request.Headers().Set("x-request-id", "an-example-uuid")
+
+	accessLog(middleware.AccessLog{}, request, dummyMiddlewareResponder)
+
+	line := output.String()
+	require.Contains(t, line, ` access [access `)
+	require.Contains(t, line, `method="GET"`)
+	require.Contains(t, line, `path="/users"`)
+	require.Contains(t, line, `status="200"`)
+	require.Contains(t, line, `bytes="11"`)
+	require.Contains(t, line, `ip="203.0.113.9"`)
+	require.Contains(t, line, `user_agent="test-agent"`)
+	require.Contains(t, line, `request_id="an-example-uuid"`)
+	require.Regexp(t, `duration_ms="\d+\.\d{3}"`, line)
+}
+
+func Test_access_log_with_percent_in_url(t *testing.T) {
+	output := &bytes.Buffer{}
+	request := accessLogRequest(output, "/users/john%20doe")
+
+	accessLog(middleware.AccessLog{Format: middleware.AccessLogCommon}, request, dummyMiddlewareResponder)
+
+	require.Contains(t, output.String(), `"GET /users/john%20doe HTTP/1.1"`)
+}
+
+func Test_access_log_with_emergency_level(t *testing.T) {
+	output := &bytes.Buffer{}
+	request := accessLogRequest(output, "/users")
+	level := log_level.EMERGENCY
+
+	accessLog(middleware.AccessLog{Level: &level}, request, dummyMiddlewareResponder)
+
+	require.Contains(t, output.String(), `severity="emerg"`)
+}
+
+func Test_access_log_bytes_of_written_response(t *testing.T) {
+	output := &bytes.Buffer{}
+	var app inter.App = accessLogRequest(output, "/users").App()
+	app.Bind("response_decorators", []inter.ResponseDecorator{})
+	app.Bind((*inter.HttpKernel)(nil), http.Kernel{App: &app})
+	app.Singleton("routes", routing.Group(
+		routing.Get("/users", func(request inter.Request) inter.Response {
+			return outcome.Html("users")
+		}),
+		routing.Get("/empty", func(request inter.Request) inter.Response {
+			return outcome.Html("").Status(net.StatusNoContent)
+		}),
+	).Middleware(middleware.AccessLog{}))
+
+	recorder := httptest.NewRecorder()
+	http.HandleHttpKernel(app, recorder, httptest.NewRequest("GET", "/users", nil))
+
+	require.Equal(t, "5", recorder.Header().Get("Content-Length"))
+	require.Contains(t, output.String(), `bytes="5"`)
+
+	output.Reset()
+	http.HandleHttpKernel(app, httptest.NewRecorder(), httptest.NewRequest("GET", "/empty", nil))
+
+	require.Contains(t, output.String(), `bytes="0"`)
+}
+
+func Test_access_log_sampling(t *testing.T) {
+	output := &bytes.Buffer{}
+
+	for i := 0; i < 20; i++ {
+		request := accessLogRequest(output, "/users")
+		accessLog(middleware.AccessLog{SampleRate: 0.000001}, request, dummyMiddlewareResponder)
+	}
+
+	require.Empty(t, output.String())
+}
+
+func Test_access_log_sampling_always_logs_server_errors(t *testing.T) {
+	output := &bytes.Buffer{}
+	request := accessLogRequest(output, "/users")
+
+	accessLog(middleware.AccessLog{SampleRate: 0.000001}, request, func(request inter.Request) inter.Response {
+		return outcome.Html("").Status(net.StatusInternalServerError)
+	})
+
+	require.Contains(t, output.String(), `status="500"`)
+}
+
+func accessLog(accessLog middleware.AccessLog, request inter.Request, next inter.Next) {
+	response := accessLog.Handle(request, next)
+	response.SetApp(request.App())
+	// The length is set by http.HandleHttpKernel when the response is written
+	response.GetHeaders().Set("Content-Length", strconv.Itoa(len(response.GetBody())))
+	accessLog.Terminate(request, response)
+}
+
+func accessLogRequest(output *bytes.Buffer, url string) inter.Request {
+	app := foundation.NewApp()
+	app.Bind("outcome_html_encoders", mock.HtmlEncoders)
+	app.Bind("config.App.Name", "testing")
+	app.Bind("config.Logging.Default", "access")
+	app.Bind("config.Logging.Channels", map[string]interface{}{
+		"access": loggers.Syslog{Writer: output, MinLevel: log_level.DEBUG},
+	})
+
+	source := httptest.NewRequest("GET", url, nil)
+	source.RemoteAddr = "203.0.113.9:5555"
+	source.Header.Set("User-Agent", "test-agent")
+	source.Header.Set("Referer", "https://example.com/")
+
+	return http.NewRequest(http.Options{App: app, Source: *source})
+}
+
+// logMessage returns the message after the structured data of a syslog line
+func logMessage(line string) string {
+	return strings.SplitN(line, `severity="info"] `, 2)[1]
+}
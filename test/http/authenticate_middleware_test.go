@@ -0,0 +1,237 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/auth"
+	"github.com/confetti-framework/foundation/http/middleware"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"github.com/confetti-framework/foundation/http/session"
+	"github.com/confetti-framework/foundation/test/mock"
+	"github.com/confetti-framework/support"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	net "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_authenticate_with_unknown_guard(t *testing.T) {
+	request := authRequest(nil)
+
+	response := middleware.Authenticate("unknown").Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusInternalServerError, response.GetStatus())
+	require.True(t, errors.Is(response.GetContent().(error), auth.GuardNotFoundError))
+}
+
+func Test_authenticate_bearer_without_token(t *testing.T) {
+	request := authRequest(nil)
+
+	response := middleware.Authenticate("api").Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusUnauthorized, response.GetStatus())
+	require.True(t, errors.Is(response.GetContent().(error), auth.UnauthenticatedError))
+	require.Equal(t, "Bearer", response.GetHeader("WWW-Authenticate"))
+}
+
+func Test_authenticate_bearer_with_invalid_token(t *testing.T) {
+	request := authRequest(map[string]string{"Authorization": "Bearer invalid"})
+
+	response := middleware.Authenticate("api").Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusUnauthorized, response.GetStatus())
+}
+
+func Test_authenticate_bearer_with_valid_token(t *testing.T) {
+	request := authRequest(map[string]string{"Authorization": "Bearer secret-token"})
+
+	var user auth.User
+	response := middleware.Authenticate("api").Handle(request, func(request inter.Request) inter.Response {
+		user = request.(*http.Request).User()
+		return outcome.Html("")
+	})
+
+	require.Equal(t, net.StatusOK, response.GetStatus())
+	require.Equal(t, "1", user.AuthIdentifier())
+}
+
+func Test_authenticate_api_key(t *testing.T) {
+	request := authRequest(map[string]string{"X-Api-Key": "secret-key"})
+
+	response := middleware.Authenticate("key").Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusOK, response.GetStatus())
+}
+
+func Test_authenticate_basic(t *testing.T) {
+	request := authRequest(map[string]string{"Authorization": basicAuth("info@example.com", "password")})
+
+	response := middleware.Authenticate("basic").Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusOK, response.GetStatus())
+}
+
+func Test_authenticate_basic_with_invalid_password(t *testing.T) {
+	request := authRequest(map[string]string{"Authorization": basicAuth("info@example.com", "invalid")})
+
+	response := middleware.Authenticate("basic").Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusUnauthorized, response.GetStatus())
+	require.True(t, errors.Is(response.GetContent().(error), auth.InvalidCredentialsError))
+	require.Equal(t, `Basic realm="Confetti", charset="UTF-8"`, response.GetHeader("WWW-Authenticate"))
+}
+
+func Test_authenticate_basic_with_unknown_user_compares_password(t *testing.T) {
+	invalid := authRequest(map[string]string{"Authorization": basicAuth("info@example.com", "invalid")})
+	unknown := authRequest(map[string]string{"Authorization": basicAuth("unknown@example.com", "invalid")})
+
+	start := time.Now()
+	middleware.Authenticate("basic").Handle(invalid, dummyMiddlewareResponder)
+	invalidDuration := time.Since(start)
+	start = time.Now()
+	response := middleware.Authenticate("basic").Handle(unknown, dummyMiddlewareResponder)
+	unknownDuration := time.Since(start)
+
+	require.Equal(t, net.StatusUnauthorized, response.GetStatus())
+	require.True(t, errors.Is(response.GetContent().(error), auth.UserNotFoundError))
+	// The password of the unknown user is compared with a hash of a higher cost
+	require.Greater(t, int64(unknownDuration), int64(invalidDuration))
+}
+
+func Test_authenticate_basic_without_challenge_on_server_error(t *testing.T) {
+	request := authRequest(map[string]string{"Authorization": basicAuth("info@example.com", "password")})
+
+	response := middleware.Authenticate("failing").Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusInternalServerError, response.GetStatus())
+	require.Equal(t, "", response.GetHeader("WWW-Authenticate"))
+}
+
+func Test_authenticate_session_after_login(t *testing.T) {
+	request := authRequest(nil)
+	current := session.NewSession("", nil)
+	id := current.Id()
+	request.App().Bind("session", current)
+	guard := auth.SessionGuard{Provider: authUsers}
+
+	_, err := guard.Attempt(request, map[string]string{"email": "info@example.com", "password": "password"})
+	require.NoError(t, err)
+	response := middleware.Authenticate("web").Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusOK, response.GetStatus())
+	require.NotEqual(t, id, current.Id())
+}
+
+func Test_authenticate_session_after_logout(t *testing.T) {
+	request := authRequest(nil)
+	request.App().Bind("session", session.NewSession("", nil))
+	guard := auth.SessionGuard{Provider: authUsers}
+	require.NoError(t, guard.Login(request, authUsers[0]))
+
+	require.NoError(t, guard.Logout(request))
+	response := middleware.Authenticate("web").Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusUnauthorized, response.GetStatus())
+}
+
+func Test_user_not_authenticated(t *testing.T) {
+	request := authRequest(nil)
+
+	_, err := request.(*http.Request).UserE()
+
+	require.True(t, errors.Is(err, auth.UnauthenticatedError))
+}
+
+func Test_sql_user_provider_validates_bcrypt_password(t *testing.T) {
+	provider := auth.NewSqlUserProvider(nil)
+
+	require.True(t, provider.ValidateCredentials(authUsers[0], map[string]string{"password": "password"}))
+	require.False(t, provider.ValidateCredentials(authUsers[0], map[string]string{"password": "invalid"}))
+}
+
+// memoryUsers finds users by exact match of the attributes
+type memoryUsers []auth.GenericUser
+
+func (m memoryUsers) ById(id string) (auth.User, error) {
+	return m.ByCredentials(map[string]string{"id": id})
+}
+
+func (m memoryUsers) ByCredentials(credentials map[string]string) (auth.User, error) {
+	for _, user := range m {
+		found := true
+		for key, value := range credentials {
+			if key != "password" && user.Get(key).String() != value {
+				found = false
+			}
+		}
+		if found {
+			return user, nil
+		}
+	}
+	return nil, errors.WithStack(auth.UserNotFoundError)
+}
+
+func (m memoryUsers) ValidateCredentials(user auth.User, credentials map[string]string) bool {
+	return auth.NewSqlUserProvider(nil).ValidateCredentials(user, credentials)
+}
+
+var authUsers = memoryUsers{newAuthUser()}
+
+// failingUsers fails as an unavailable database
+type failingUsers struct{}
+
+func (f failingUsers) ById(id string) (auth.User, error) {
+	return nil, errors.New("database is unavailable").Status(net.StatusInternalServerError)
+}
+
+func (f failingUsers) ByCredentials(credentials map[string]string) (auth.User, error) {
+	return nil, errors.New("database is unavailable").Status(net.StatusInternalServerError)
+}
+
+func (f failingUsers) ValidateCredentials(user auth.User, credentials map[string]string) bool {
+	return false
+}
+
+func newAuthUser() auth.GenericUser {
+	password, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	token := sha256.Sum256([]byte("secret-token"))
+
+	return auth.GenericUser{Attributes: support.NewMap(map[string]interface{}{
+		"id":        "1",
+		"email":     "info@example.com",
+		"password":  string(password),
+		"api_token": hex.EncodeToString(token[:]),
+		"api_key":   "secret-key",
+	})}
+}
+
+func basicAuth(username string, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+func authRequest(headers map[string]string) inter.Request {
+	app := foundation.NewApp()
+	app.Bind("outcome_html_encoders", mock.HtmlEncoders)
+	app.Bind("default_response_outcome", outcome.Html)
+	app.Bind("auth_guards", auth.Guards{
+		"web":     auth.SessionGuard{Provider: authUsers},
+		"api":     auth.BearerGuard{Provider: authUsers, Hash: true},
+		"basic":   auth.BasicGuard{Provider: authUsers},
+		"key":     auth.ApiKeyGuard{Provider: authUsers},
+		"failing": auth.BasicGuard{Provider: failingUsers{}},
+	})
+
+	source := httptest.NewRequest("GET", "/orders", nil)
+	for key, value := range headers {
+		source.Header.Set(key, value)
+	}
+
+	return http.NewRequest(http.Options{App: app, Source: *source})
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/auth"
+	"github.com/confetti-framework/foundation/http/middleware"
+	"github.com/stretchr/testify/require"
+	net "net/http"
+	"testing"
+)
+
+type post struct {
+	AuthorId string
+}
+
+type postPolicy struct{}
+
+func (p postPolicy) Update(user auth.User, post post) bool {
+	return user.AuthIdentifier() == post.AuthorId
+}
+
+func (p postPolicy) ViewAny(_ auth.User, _ *post) bool {
+	return true
+}
+
+func Test_gate_defined_ability(t *testing.T) {
+	gate := auth.NewGate().Define("view-dashboard", func(user auth.User, _ ...interface{}) bool {
+		return user.AuthIdentifier() == "1"
+	})
+
+	require.True(t, gate.Allows(authUsers[0], "view-dashboard"))
+	require.True(t, gate.Denies(authUsers[0], "unknown"))
+}
+
+func Test_gate_without_user(t *testing.T) {
+	gate := auth.NewGate().Define("view-dashboard", func(_ auth.User, _ ...interface{}) bool {
+		return true
+	})
+
+	require.False(t, gate.Allows(nil, "view-dashboard"))
+}
+
+func Test_gate_policy(t *testing.T) {
+	gate := auth.NewGate().Policy(post{}, postPolicy{})
+
+	require.True(t, gate.Allows(authUsers[0], "update", post{AuthorId: "1"}))
+	require.True(t, gate.Allows(authUsers[0], "update", &post{AuthorId: "1"}))
+	require.False(t, gate.Allows(authUsers[0], "update", post{AuthorId: "2"}))
+	require.True(t, gate.Allows(authUsers[0], "view-any", post{}))
+	require.False(t, gate.Allows(authUsers[0], "delete", post{AuthorId: "1"}))
+}
+
+func Test_gate_before_hook(t *testing.T) {
+	gate := auth.NewGate().
+		Policy(post{}, postPolicy{}).
+		Before(func(user auth.User, _ string, _ ...interface{}) (bool, bool) {
+			return true, user.AuthIdentifier() == "1"
+		})
+
+	require.True(t, gate.Allows(authUsers[0], "update", post{AuthorId: "2"}))
+	require.True(t, gate.Allows(authUsers[0], "unknown"))
+}
+
+func Test_gate_authorize(t *testing.T) {
+	err := auth.NewGate().Authorize(authUsers[0], "unknown")
+
+	require.True(t, errors.Is(err, auth.ForbiddenError))
+}
+
+func Test_request_can(t *testing.T) {
+	request := authRequest(nil)
+	request.App().Bind("gate", auth.NewGate().Policy(post{}, postPolicy{}))
+
+	require.False(t, request.(*http.Request).Can("update", post{AuthorId: "1"}))
+
+	request.App().Bind("user", authUsers[0])
+	require.True(t, request.(*http.Request).Can("update", post{AuthorId: "1"}))
+	require.False(t, request.(*http.Request).Can("update", post{AuthorId: "2"}))
+}
+
+func Test_can_middleware_allows(t *testing.T) {
+	request := authRequest(nil)
+	request.App().Bind("user", authUsers[0])
+	request.App().Bind("gate", auth.NewGate().Define("view-dashboard", func(_ auth.User, _ ...interface{}) bool {
+		return true
+	}))
+
+	response := middleware.Can("view-dashboard").Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusOK, response.GetStatus())
+}
+
+func Test_can_middleware_forbidden(t *testing.T) {
+	request := authRequest(nil)
+	request.App().Bind("user", authUsers[0])
+
+	response := middleware.Can("view-dashboard").Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusForbidden, response.GetStatus())
+	require.True(t, errors.Is(response.GetContent().(error), auth.ForbiddenError))
+}
+
+func Test_can_middleware_without_user(t *testing.T) {
+	request := authRequest(nil)
+
+	response := middleware.Can("view-dashboard").Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusUnauthorized, response.GetStatus())
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation"
+	"github.com/confetti-framework/foundation/encoder"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/maintenance"
+	"github.com/confetti-framework/foundation/http/middleware"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"github.com/confetti-framework/foundation/test/mock"
+	"github.com/stretchr/testify/require"
+	"html/template"
+	net "net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_maintenance_mode_inactive(t *testing.T) {
+	request := maintenanceRequest(filepath.Join(t.TempDir(), "down"), "/", "203.0.113.9:5555")
+
+	response := middleware.CheckForMaintenanceMode{}.Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusOK, response.GetStatus())
+}
+
+func Test_maintenance_mode_active(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "down")
+	require.NoError(t, maintenance.Down(path, maintenance.Mode{Retry: 60}))
+	request := maintenanceRequest(path, "/", "203.0.113.9:5555")
+
+	response := middleware.CheckForMaintenanceMode{}.Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusServiceUnavailable, response.GetStatus())
+	require.True(t, errors.Is(response.GetContent().(error), middleware.MaintenanceModeError))
+	require.Equal(t, "60", response.GetHeader("Retry-After"))
+}
+
+func Test_maintenance_mode_with_custom_status(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "down")
+	require.NoError(t, maintenance.Down(path, maintenance.Mode{Status: net.StatusTooManyRequests}))
+	request := maintenanceRequest(path, "/", "203.0.113.9:5555")
+
+	response := middleware.CheckForMaintenanceMode{}.Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusTooManyRequests, response.GetStatus())
+}
+
+func Test_maintenance_mode_with_view(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "down")
+	require.NoError(t, maintenance.Down(path, maintenance.Mode{Retry: 60, Template: "Back in {{ .Retry }} seconds"}))
+	request := maintenanceRequest(path, "/", "203.0.113.9:5555")
+	request.App().Bind("outcome_html_encoders", append([]inter.Encoder{encoder.ViewToHtml{}}, mock.HtmlEncoders...))
+
+	response := middleware.CheckForMaintenanceMode{}.Handle(request, dummyMiddlewareResponder)
+	response.SetApp(request.App())
+
+	require.Equal(t, net.StatusServiceUnavailable, response.GetStatus())
+	require.Equal(t, "Back in 60 seconds", response.GetBody())
+}
+
+func Test_maintenance_mode_allowed_ip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "down")
+	require.NoError(t, maintenance.Down(path, maintenance.Mode{Allow: []string{"10.0.0.0/8"}}))
+
+	allowed := middleware.CheckForMaintenanceMode{}.Handle(maintenanceRequest(path, "/", "10.1.2.3:5555"), dummyMiddlewareResponder)
+	denied := middleware.CheckForMaintenanceMode{}.Handle(maintenanceRequest(path, "/", "203.0.113.9:5555"), dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusOK, allowed.GetStatus())
+	require.Equal(t, net.StatusServiceUnavailable, denied.GetStatus())
+}
+
+func Test_maintenance_mode_secret_sets_bypass_cookie(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "down")
+	require.NoError(t, maintenance.Down(path, maintenance.Mode{Secret: "1630542a"}))
+
+	response := middleware.CheckForMaintenanceMode{}.Handle(maintenanceRequest(path, "/1630542a", "203.0.113.9:5555"), dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusFound, response.GetStatus())
+	require.Len(t, response.GetCookies(), 1)
+	cookie := response.GetCookies()[0]
+	require.True(t, maintenance.ValidBypassCookie("1630542a", cookie.Value))
+
+	request := maintenanceRequest(path, "/", "203.0.113.9:5555")
+	request.Source().Header.Set("Cookie", cookie.String())
+	bypassed := middleware.CheckForMaintenanceMode{}.Handle(request, dummyMiddlewareResponder)
+	require.Equal(t, net.StatusOK, bypassed.GetStatus())
+}
+
+func Test_maintenance_bypass_cookie_with_other_secret(t *testing.T) {
+	cookie := maintenance.BypassCookie("other", time.Now().Add(time.Hour))
+
+	require.False(t, maintenance.ValidBypassCookie("1630542a", cookie))
+}
+
+func Test_maintenance_bypass_cookie_expired(t *testing.T) {
+	cookie := maintenance.BypassCookie("1630542a", time.Now().Add(-time.Hour))
+
+	require.False(t, maintenance.ValidBypassCookie("1630542a", cookie))
+}
+
+func maintenanceRequest(path string, url string, remoteAddr string) inter.Request {
+	app := foundation.NewApp()
+	app.Bind("outcome_html_encoders", mock.HtmlEncoders)
+	app.Bind("default_response_outcome", outcome.Html)
+	app.Bind("maintenance_path", path)
+	app.Bind("template_builder", func(template *template.Template) (*template.Template, error) {
+		return template, nil
+	})
+
+	source := httptest.NewRequest("GET", url, nil)
+	source.RemoteAddr = remoteAddr
+
+	return http.NewRequest(http.Options{App: app, Source: *source})
+}
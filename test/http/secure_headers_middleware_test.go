@@ -0,0 +1,118 @@
+package http
+
+import (
+	"crypto/tls"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/middleware"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"github.com/confetti-framework/foundation/http/view_helper"
+	"github.com/stretchr/testify/require"
+	"html/template"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_secure_headers_defaults(t *testing.T) {
+	response := middleware.SecureHeaders{}.Handle(secureHeadersRequest(false), dummyMiddlewareResponder)
+
+	require.Equal(t, "nosniff", response.GetHeader("X-Content-Type-Options"))
+	require.Equal(t, "SAMEORIGIN", response.GetHeader("X-Frame-Options"))
+	require.Equal(t, "strict-origin-when-cross-origin", response.GetHeader("Referrer-Policy"))
+	require.Empty(t, response.GetHeader("Permissions-Policy"))
+	require.Empty(t, response.GetHeader("Content-Security-Policy"))
+	require.Empty(t, response.GetHeader("Strict-Transport-Security"))
+}
+
+func Test_secure_headers_hsts_over_https(t *testing.T) {
+	headers := middleware.SecureHeaders{HstsMaxAge: time.Hour, HstsIncludeSubdomains: true, HstsPreload: true}
+
+	response := headers.Handle(secureHeadersRequest(true), dummyMiddlewareResponder)
+
+	require.Equal(t, "max-age=3600; includeSubDomains; preload", response.GetHeader("Strict-Transport-Security"))
+}
+
+func Test_secure_headers_default_hsts(t *testing.T) {
+	response := middleware.SecureHeaders{}.Handle(secureHeadersRequest(true), dummyMiddlewareResponder)
+
+	require.Equal(t, "max-age=31536000", response.GetHeader("Strict-Transport-Security"))
+}
+
+func Test_secure_headers_disabled(t *testing.T) {
+	headers := middleware.SecureHeaders{HstsMaxAge: -1, FrameOptions: middleware.DisableHeader}
+
+	response := headers.Handle(secureHeadersRequest(true), dummyMiddlewareResponder)
+
+	require.Empty(t, response.GetHeader("Strict-Transport-Security"))
+	require.Empty(t, response.GetHeader("X-Frame-Options"))
+}
+
+func Test_secure_headers_do_not_replace_header_of_controller(t *testing.T) {
+	response := middleware.SecureHeaders{}.Handle(secureHeadersRequest(false), func(_ inter.Request) inter.Response {
+		response := outcome.Html("")
+		response.GetHeaders().Set("X-Frame-Options", "DENY")
+		return response
+	})
+
+	require.Equal(t, "DENY", response.GetHeader("X-Frame-Options"))
+}
+
+func Test_secure_headers_content_security_policy_with_nonce(t *testing.T) {
+	headers := middleware.SecureHeaders{
+		PermissionsPolicy:     "camera=()",
+		ContentSecurityPolicy: "script-src 'self' 'nonce-{nonce}'",
+	}
+
+	var nonce, html string
+	response := headers.Handle(secureHeadersRequest(false), func(request inter.Request) inter.Response {
+		nonce = request.App().Make("csp_nonce").(string)
+		var err error
+		html, err = view_helper.ContentByView(
+			nonceView{},
+			func(template *template.Template) (*template.Template, error) { return template, nil },
+			view_helper.TemplateFunctions(request.App()),
+		)
+		require.NoError(t, err)
+		return outcome.Html("")
+	})
+
+	require.Len(t, nonce, 22)
+	require.Equal(t, "script-src 'self' 'nonce-"+nonce+"'", response.GetHeader("Content-Security-Policy"))
+	require.Equal(t, "camera=()", response.GetHeader("Permissions-Policy"))
+	require.Equal(t, `<script nonce="`+nonce+`"></script>`, html)
+}
+
+func Test_secure_headers_nonce_per_request(t *testing.T) {
+	headers := middleware.SecureHeaders{ContentSecurityPolicy: "script-src 'nonce-{nonce}'"}
+
+	first := headers.Handle(secureHeadersRequest(false), dummyMiddlewareResponder)
+	second := headers.Handle(secureHeadersRequest(false), dummyMiddlewareResponder)
+
+	require.NotEqual(t, first.GetHeader("Content-Security-Policy"), second.GetHeader("Content-Security-Policy"))
+}
+
+func Test_secure_headers_report_only(t *testing.T) {
+	headers := middleware.SecureHeaders{ContentSecurityPolicy: "default-src 'self'", ContentSecurityPolicyReportOnly: true}
+
+	response := headers.Handle(secureHeadersRequest(false), dummyMiddlewareResponder)
+
+	require.Empty(t, response.GetHeader("Content-Security-Policy"))
+	require.Equal(t, "default-src 'self'", response.GetHeader("Content-Security-Policy-Report-Only"))
+}
+
+type nonceView struct{}
+
+func (n nonceView) Template() string {
+	return `<script nonce="{{ csp_nonce }}"></script>`
+}
+
+func secureHeadersRequest(secure bool) inter.Request {
+	source := httptest.NewRequest("GET", "/", nil)
+	if secure {
+		source.TLS = &tls.ConnectionState{}
+	}
+
+	return http.NewRequest(http.Options{App: foundation.NewApp(), Source: *source})
+}
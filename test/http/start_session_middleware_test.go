@@ -0,0 +1,248 @@
+package http
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/http_helper"
+	"github.com/confetti-framework/foundation/http/middleware"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"github.com/confetti-framework/foundation/http/session"
+	"github.com/confetti-framework/foundation/test/mock"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	net "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_session_not_started(t *testing.T) {
+	request := sessionRequest("")
+
+	_, err := request.(*http.Request).SessionE()
+
+	require.True(t, errors.Is(err, session.NotStartedError))
+}
+
+func Test_session_with_cookie_driver(t *testing.T) {
+	startSession := middleware.StartSession{}
+
+	cookie := sessionCookie(startSession.Handle(sessionRequest(""), func(request inter.Request) inter.Response {
+		request.(*http.Request).Session().Put("user_id", 12)
+		return outcome.Html("")
+	}))
+	require.True(t, cookie.HttpOnly)
+	require.Equal(t, "/", cookie.Path)
+	require.Equal(t, net.SameSiteLaxMode, cookie.SameSite)
+
+	var userId int
+	startSession.Handle(sessionRequest(cookie.Value), func(request inter.Request) inter.Response {
+		userId = request.(*http.Request).Session().Get("user_id").Int()
+		return outcome.Html("")
+	})
+	require.Equal(t, 12, userId)
+}
+
+func Test_session_with_cookie_driver_refuses_short_key(t *testing.T) {
+	request := sessionRequest("")
+	request.App().Bind("config.App.Key", "short-key")
+	request.App().Bind("outcome_html_encoders", mock.HtmlEncoders)
+	request.App().Bind("default_response_outcome", outcome.Html)
+
+	response := middleware.StartSession{}.Handle(request, func(request inter.Request) inter.Response {
+		t.Fatal("the session should not be started")
+		return nil
+	})
+
+	require.Equal(t, net.StatusInternalServerError, response.GetStatus())
+	require.True(t, errors.Is(response.GetContent().(error), http_helper.InvalidAppKeyError))
+	require.Nil(t, sessionCookie(response))
+}
+
+func Test_session_with_cookie_driver_refuses_empty_key(t *testing.T) {
+	request := sessionRequest("")
+	request.App().Bind("config.App.Key", "")
+	request.App().Bind("outcome_html_encoders", mock.HtmlEncoders)
+	request.App().Bind("default_response_outcome", outcome.Html)
+
+	response := middleware.StartSession{}.Handle(request, func(request inter.Request) inter.Response {
+		t.Fatal("the session should not be started")
+		return nil
+	})
+
+	require.True(t, errors.Is(response.GetContent().(error), http_helper.InvalidAppKeyError))
+}
+
+func Test_session_with_changed_cookie(t *testing.T) {
+	startSession := middleware.StartSession{}
+
+	var has bool
+	startSession.Handle(sessionRequest("invalid"), func(request inter.Request) inter.Response {
+		has = request.(*http.Request).Session().Has("user_id")
+		return outcome.Html("")
+	})
+
+	require.False(t, has)
+}
+
+func Test_session_with_file_driver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sessions")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	startSession := middleware.StartSession{Driver: session.FileDriver{Path: dir}}
+
+	cookie := sessionCookie(startSession.Handle(sessionRequest(""), func(request inter.Request) inter.Response {
+		request.(*http.Request).Session().Put("name", "Confetti")
+		return outcome.Html("")
+	}))
+	require.FileExists(t, filepath.Join(dir, cookie.Value))
+
+	var name string
+	startSession.Handle(sessionRequest(cookie.Value), func(request inter.Request) inter.Response {
+		name = request.(*http.Request).Session().Get("name").String()
+		return outcome.Html("")
+	})
+	require.Equal(t, "Confetti", name)
+}
+
+func Test_session_file_driver_ignores_unknown_id(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sessions")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	startSession := middleware.StartSession{Driver: session.FileDriver{Path: dir}}
+	unknown := session.NewId()
+
+	cookie := sessionCookie(startSession.Handle(sessionRequest(unknown), dummyMiddlewareResponder))
+
+	require.NotEqual(t, unknown, cookie.Value)
+}
+
+func Test_session_file_driver_garbage_collection(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sessions")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	driver := session.FileDriver{Path: dir}
+	id := session.NewId()
+	_, err = driver.Save(id, []byte("{}"), time.Hour)
+	require.NoError(t, err)
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, id), old, old))
+
+	require.NoError(t, driver.Gc(time.Hour))
+
+	require.NoFileExists(t, filepath.Join(dir, id))
+}
+
+func Test_session_garbage_collection_after_response(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sessions")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	startSession := middleware.StartSession{Driver: session.FileDriver{Path: dir}, Lottery: [2]int{1, 1}}
+	id := session.NewId()
+	_, err = startSession.Driver.Save(id, []byte("{}"), time.Hour)
+	require.NoError(t, err)
+	old := time.Now().Add(-3 * time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, id), old, old))
+	request := sessionRequest("")
+
+	response := startSession.Handle(request, func(request inter.Request) inter.Response {
+		return outcome.Html("")
+	})
+	require.FileExists(t, filepath.Join(dir, id))
+
+	startSession.Terminate(request, response)
+	require.NoFileExists(t, filepath.Join(dir, id))
+}
+
+func Test_session_flash_data_is_available_in_next_request_only(t *testing.T) {
+	startSession := middleware.StartSession{}
+	values := func(cookie string) (string, *net.Cookie) {
+		var status string
+		response := startSession.Handle(sessionRequest(cookie), func(request inter.Request) inter.Response {
+			status = request.(*http.Request).Session().GetOr("status", "").String()
+			return outcome.Html("")
+		})
+		return status, sessionCookie(response)
+	}
+
+	cookie := sessionCookie(startSession.Handle(sessionRequest(""), func(request inter.Request) inter.Response {
+		request.(*http.Request).Session().Flash("status", "saved")
+		return outcome.Html("")
+	}))
+	first, cookie := values(cookie.Value)
+	second, _ := values(cookie.Value)
+
+	require.Equal(t, "saved", first)
+	require.Equal(t, "", second)
+}
+
+func Test_session_regenerate_keeps_data(t *testing.T) {
+	current := session.NewSession("", nil)
+	id := current.Id()
+	current.Put("user_id", 12)
+
+	current.Regenerate()
+
+	require.NotEqual(t, id, current.Id())
+	require.Equal(t, id, current.PreviousId())
+	require.Equal(t, 12, current.Get("user_id").Int())
+}
+
+func Test_session_regenerate_destroys_previous_session(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sessions")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	startSession := middleware.StartSession{Driver: session.FileDriver{Path: dir}}
+	first := sessionCookie(startSession.Handle(sessionRequest(""), dummyMiddlewareResponder))
+
+	second := sessionCookie(startSession.Handle(sessionRequest(first.Value), func(request inter.Request) inter.Response {
+		request.(*http.Request).Session().Regenerate()
+		return outcome.Html("")
+	}))
+
+	require.NotEqual(t, first.Value, second.Value)
+	require.NoFileExists(t, filepath.Join(dir, first.Value))
+	require.FileExists(t, filepath.Join(dir, second.Value))
+}
+
+func Test_session_invalidate(t *testing.T) {
+	current := session.NewSession("", nil)
+	id := current.Id()
+	current.Put("user_id", 12)
+
+	current.Invalidate()
+
+	require.NotEqual(t, id, current.Id())
+	require.Empty(t, current.All())
+}
+
+func Test_session_with_invalid_id_gets_new_id(t *testing.T) {
+	current := session.NewSession("../../etc/passwd", nil)
+
+	require.True(t, session.IsValidId(current.Id()))
+}
+
+func sessionRequest(cookie string) inter.Request {
+	app := foundation.NewApp()
+	app.Bind("config.App.Key", "base-key-for-sessions-of-32-chars")
+
+	source := httptest.NewRequest("GET", "/users", nil)
+	if cookie != "" {
+		source.AddCookie(&net.Cookie{Name: "confetti_session", Value: cookie})
+	}
+
+	return http.NewRequest(http.Options{App: app, Source: *source})
+}
+
+func sessionCookie(response inter.Response) *net.Cookie {
+	for _, cookie := range response.GetCookies() {
+		if cookie.Name == "confetti_session" {
+			return &cookie
+		}
+	}
+	return nil
+}
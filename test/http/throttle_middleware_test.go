@@ -0,0 +1,135 @@
+package http
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/middleware"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"github.com/confetti-framework/foundation/test/mock"
+	"github.com/stretchr/testify/require"
+	net "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_throttle_allows_requests_within_limit(t *testing.T) {
+	throttle := middleware.Throttle{MaxAttempts: 2, Decay: time.Minute, Store: middleware.NewMemoryRateLimitStore()}
+
+	response := throttle.Handle(throttleRequest("203.0.113.9:5555", nil), dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusOK, response.GetStatus())
+	require.Equal(t, "2", response.GetHeader("X-RateLimit-Limit"))
+	require.Equal(t, "1", response.GetHeader("X-RateLimit-Remaining"))
+}
+
+func Test_throttle_rejects_requests_above_limit(t *testing.T) {
+	throttle := middleware.Throttle{MaxAttempts: 2, Decay: time.Minute, Store: middleware.NewMemoryRateLimitStore()}
+
+	throttle.Handle(throttleRequest("203.0.113.9:5555", nil), dummyMiddlewareResponder)
+	throttle.Handle(throttleRequest("203.0.113.9:5555", nil), dummyMiddlewareResponder)
+	response := throttle.Handle(throttleRequest("203.0.113.9:5555", nil), dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusTooManyRequests, response.GetStatus())
+	require.True(t, errors.Is(response.GetContent().(error), middleware.TooManyRequestsError))
+	require.Equal(t, "0", response.GetHeader("X-RateLimit-Remaining"))
+	require.Equal(t, "30", response.GetHeader("Retry-After"))
+	require.NotEmpty(t, response.GetHeader("X-RateLimit-Reset"))
+}
+
+func Test_throttle_limits_per_ip(t *testing.T) {
+	throttle := middleware.Throttle{MaxAttempts: 1, Decay: time.Minute, Store: middleware.NewMemoryRateLimitStore()}
+
+	throttle.Handle(throttleRequest("203.0.113.9:5555", nil), dummyMiddlewareResponder)
+	response := throttle.Handle(throttleRequest("203.0.113.10:5555", nil), dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusOK, response.GetStatus())
+}
+
+func Test_throttle_by_header(t *testing.T) {
+	throttle := middleware.Throttle{
+		MaxAttempts: 1,
+		Decay:       time.Minute,
+		Key:         middleware.ThrottleByHeader("X-Api-Key"),
+		Store:       middleware.NewMemoryRateLimitStore(),
+	}
+
+	throttle.Handle(throttleRequest("203.0.113.9:5555", map[string]string{"X-Api-Key": "a"}), dummyMiddlewareResponder)
+	other := throttle.Handle(throttleRequest("203.0.113.9:5555", map[string]string{"X-Api-Key": "b"}), dummyMiddlewareResponder)
+	same := throttle.Handle(throttleRequest("203.0.113.10:5555", map[string]string{"X-Api-Key": "a"}), dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusOK, other.GetStatus())
+	require.Equal(t, net.StatusTooManyRequests, same.GetStatus())
+}
+
+func Test_throttle_refills_tokens(t *testing.T) {
+	throttle := middleware.Throttle{MaxAttempts: 1, Decay: 20 * time.Millisecond, Store: middleware.NewMemoryRateLimitStore()}
+
+	throttle.Handle(throttleRequest("203.0.113.9:5555", nil), dummyMiddlewareResponder)
+	time.Sleep(25 * time.Millisecond)
+	response := throttle.Handle(throttleRequest("203.0.113.9:5555", nil), dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusOK, response.GetStatus())
+}
+
+func Test_throttle_store_from_container(t *testing.T) {
+	store := &countingStore{}
+	request := throttleRequest("203.0.113.9:5555", nil)
+	request.App().Bind("rate_limit_store", store)
+
+	middleware.Throttle{MaxAttempts: 1, Decay: time.Minute}.Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, []string{"1/1m0s|203.0.113.9"}, store.keys)
+}
+
+func Test_throttle_by_user(t *testing.T) {
+	store := &countingStore{}
+	throttle := middleware.Throttle{MaxAttempts: 1, Decay: time.Minute, Key: middleware.ThrottleByUser, Store: store}
+	request := throttleRequest("203.0.113.9:5555", nil)
+	request.App().Bind("user", newAuthUser())
+
+	throttle.Handle(request, dummyMiddlewareResponder)
+	throttle.Handle(throttleRequest("203.0.113.9:5555", nil), dummyMiddlewareResponder)
+
+	require.Equal(t, []string{"1/1m0s|user:1", "1/1m0s|203.0.113.9"}, store.keys)
+}
+
+func Test_throttle_rejects_request_when_store_fails(t *testing.T) {
+	throttle := middleware.Throttle{MaxAttempts: 1, Decay: time.Minute, Store: failingStore{}}
+
+	response := throttle.Handle(throttleRequest("203.0.113.9:5555", nil), dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusServiceUnavailable, response.GetStatus())
+	require.True(t, errors.Is(response.GetContent().(error), middleware.RateLimitStoreError))
+}
+
+type failingStore struct{}
+
+func (f failingStore) Take(_ string, _ int, _ time.Duration) (middleware.RateLimit, error) {
+	return middleware.RateLimit{}, errors.New("connection refused")
+}
+
+type countingStore struct {
+	keys []string
+}
+
+func (c *countingStore) Take(key string, _ int, _ time.Duration) (middleware.RateLimit, error) {
+	c.keys = append(c.keys, key)
+	return middleware.RateLimit{Allowed: true}, nil
+}
+
+func throttleRequest(remoteAddr string, headers map[string]string) inter.Request {
+	app := foundation.NewApp()
+	app.Bind("outcome_html_encoders", mock.HtmlEncoders)
+	app.Bind("default_response_outcome", outcome.Html)
+
+	source := httptest.NewRequest("GET", "/users", nil)
+	source.RemoteAddr = remoteAddr
+	for key, value := range headers {
+		source.Header.Set(key, value)
+	}
+
+	return http.NewRequest(http.Options{App: app, Source: *source})
+}
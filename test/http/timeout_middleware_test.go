@@ -0,0 +1,153 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/middleware"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"github.com/confetti-framework/foundation/loggers"
+	"github.com/confetti-framework/foundation/test/mock"
+	"github.com/confetti-framework/syslog/log_level"
+	"github.com/stretchr/testify/require"
+	net "net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_timeout_returns_response_within_deadline(t *testing.T) {
+	request := timeoutRequest()
+	response := middleware.Timeout(time.Second).Handle(request, dummyMiddlewareResponder)
+	response.SetApp(request.App())
+
+	require.Equal(t, net.StatusOK, response.GetStatus())
+	require.Equal(t, "hello world", response.GetBody())
+}
+
+func Test_timeout_adds_deadline_to_request_context(t *testing.T) {
+	var hasDeadline bool
+	middleware.Timeout(time.Second).Handle(timeoutRequest(), func(request inter.Request) inter.Response {
+		source := request.Source()
+		_, hasDeadline = source.Context().Deadline()
+		return outcome.Html("")
+	})
+
+	require.True(t, hasDeadline)
+}
+
+func Test_timeout_exceeded(t *testing.T) {
+	cancelled := make(chan error, 1)
+	response := middleware.Timeout(10*time.Millisecond).Handle(timeoutRequest(), func(request inter.Request) inter.Response {
+		source := request.Source()
+		<-source.Context().Done()
+		cancelled <- source.Context().Err()
+		time.Sleep(10 * time.Millisecond)
+		return outcome.Html("too late")
+	})
+
+	require.Equal(t, net.StatusServiceUnavailable, response.GetStatus())
+	require.True(t, errors.Is(response.GetContent().(error), middleware.TimeoutError))
+	require.Equal(t, context.DeadlineExceeded, <-cancelled)
+}
+
+func Test_timeout_passes_panic_from_controller(t *testing.T) {
+	require.PanicsWithValue(t, "controller failed", func() {
+		middleware.Timeout(time.Second).Handle(timeoutRequest(), func(request inter.Request) inter.Response {
+			panic("controller failed")
+		})
+	})
+}
+
+func Test_timeout_logs_panic_of_late_controller(t *testing.T) {
+	output := &lockedBuffer{}
+	request := timeoutRequest()
+	request.App().Bind("config.App.Name", "testing")
+	request.App().Bind("config.Logging.Default", "errors")
+	request.App().Bind("config.Logging.Channels", map[string]interface{}{
+		"errors": loggers.Syslog{Writer: output, MinLevel: log_level.DEBUG},
+	})
+
+	response := middleware.Timeout(10*time.Millisecond).Handle(request, func(request inter.Request) inter.Response {
+		source := request.Source()
+		<-source.Context().Done()
+		panic("report failed")
+	})
+
+	require.Equal(t, net.StatusServiceUnavailable, response.GetStatus())
+	require.Eventually(t, func() bool {
+		return strings.Contains(output.String(), "report failed")
+	}, time.Second, 5*time.Millisecond)
+}
+
+// lockedBuffer can be written by the goroutine of the controller while the test reads it
+type lockedBuffer struct {
+	lock   sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (l *lockedBuffer) Write(p []byte) (int, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.buffer.Write(p)
+}
+
+func (l *lockedBuffer) String() string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.buffer.String()
+}
+
+// Run with -race: the late controller may not share the container with the
+// timeout response
+func Test_timeout_late_controller_uses_copy_of_container(t *testing.T) {
+	request := timeoutRequest()
+	request.App().Singleton("report", func() string { return "report" })
+	finished := make(chan string, 1)
+
+	response := middleware.Timeout(10*time.Millisecond).Handle(request, func(request inter.Request) inter.Response {
+		source := request.Source()
+		<-source.Context().Done()
+		for i := 0; i < 100; i++ {
+			request.App().Bind("late", i)
+			request.App().Make("report")
+			request.Headers().Set("X-Late", "yes")
+		}
+		finished <- request.App().Make("report").(string)
+		return outcome.Html("too late")
+	})
+	for i := 0; i < 100; i++ {
+		request.App().Bind("response", i)
+		request.App().Make("report")
+		request.Headers().Get("X-Late")
+	}
+	response.SetApp(request.App())
+
+	require.Equal(t, net.StatusServiceUnavailable, response.GetStatus())
+	require.Equal(t, "report", <-finished)
+	_, err := request.App().MakeE("late")
+	require.Error(t, err)
+}
+
+func Test_timeout_keeps_bindings_of_controller_within_deadline(t *testing.T) {
+	request := timeoutRequest()
+
+	middleware.Timeout(time.Second).Handle(request, func(request inter.Request) inter.Response {
+		request.App().Bind("user", "Janet")
+		return outcome.Html("")
+	})
+
+	require.Equal(t, "Janet", request.App().Make("user"))
+}
+
+func timeoutRequest() inter.Request {
+	app := foundation.NewApp()
+	app.Bind("outcome_html_encoders", mock.HtmlEncoders)
+	app.Bind("default_response_outcome", outcome.Html)
+
+	return http.NewRequest(http.Options{App: app, Url: "/report"})
+}
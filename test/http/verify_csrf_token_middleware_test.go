@@ -0,0 +1,189 @@
+package http
+
+import (
+	"bytes"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/middleware"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"github.com/confetti-framework/foundation/http/session"
+	"github.com/confetti-framework/foundation/http/view_helper"
+	"github.com/confetti-framework/foundation/test/mock"
+	"github.com/stretchr/testify/require"
+	"html/template"
+	"mime/multipart"
+	net "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_csrf_get_request_without_token(t *testing.T) {
+	request, _ := csrfRequest("GET", "/profile", "", nil)
+
+	response := middleware.VerifyCsrfToken{}.Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusOK, response.GetStatus())
+}
+
+func Test_csrf_post_request_without_token(t *testing.T) {
+	request, _ := csrfRequest("POST", "/profile", "", nil)
+
+	response := middleware.VerifyCsrfToken{}.Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, 419, response.GetStatus())
+	require.True(t, errors.Is(response.GetContent().(error), middleware.CsrfTokenMismatchError))
+}
+
+func Test_csrf_post_request_with_invalid_token(t *testing.T) {
+	request, _ := csrfRequest("DELETE", "/profile", "", map[string]string{"X-CSRF-Token": "invalid"})
+
+	response := middleware.VerifyCsrfToken{}.Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, 419, response.GetStatus())
+}
+
+func Test_csrf_post_request_with_token_in_header(t *testing.T) {
+	request, current := csrfRequest("POST", "/profile", "", nil)
+	request.Source().Header.Set("X-CSRF-Token", current.Token())
+
+	response := middleware.VerifyCsrfToken{}.Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusOK, response.GetStatus())
+}
+
+func Test_csrf_post_request_with_token_in_form(t *testing.T) {
+	current := session.NewSession("", nil)
+	request, _ := csrfRequestWithSession("POST", "/profile", "name=Confetti&_token="+current.Token(), nil, current)
+
+	var body string
+	response := middleware.VerifyCsrfToken{}.Handle(request, func(request inter.Request) inter.Response {
+		body = request.Body()
+		return outcome.Html("")
+	})
+
+	require.Equal(t, net.StatusOK, response.GetStatus())
+	require.Equal(t, "name=Confetti&_token="+current.Token(), body)
+}
+
+func Test_csrf_post_request_with_token_in_form_larger_than_limit(t *testing.T) {
+	current := session.NewSession("", nil)
+	photo := strings.Repeat("a", int(middleware.MaxFormSize)+1)
+	request, _ := csrfRequestWithSession("POST", "/photos", "_token="+current.Token()+"&photo="+photo, nil, current)
+
+	var body string
+	response := middleware.VerifyCsrfToken{}.Handle(request, func(request inter.Request) inter.Response {
+		body = request.Body()
+		return outcome.Html("")
+	})
+
+	require.Equal(t, net.StatusOK, response.GetStatus())
+	require.Equal(t, "_token="+current.Token()+"&photo="+photo, body)
+}
+
+func Test_csrf_post_request_with_token_in_multipart_form_larger_than_limit(t *testing.T) {
+	current := session.NewSession("", nil)
+	content := &bytes.Buffer{}
+	form := multipart.NewWriter(content)
+	require.NoError(t, form.WriteField("_token", current.Token()))
+	file, err := form.CreateFormFile("photo", "sunset.jpg")
+	require.NoError(t, err)
+	_, err = file.Write(bytes.Repeat([]byte("a"), int(middleware.MaxFormSize)+1))
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+	headers := map[string]string{"Content-Type": form.FormDataContentType()}
+	request, _ := csrfRequestWithSession("POST", "/photos", content.String(), headers, current)
+
+	var body string
+	response := middleware.VerifyCsrfToken{}.Handle(request, func(request inter.Request) inter.Response {
+		body = request.Body()
+		return outcome.Html("")
+	})
+
+	require.Equal(t, net.StatusOK, response.GetStatus())
+	require.Equal(t, content.String(), body)
+}
+
+func Test_csrf_token_after_limit_is_ignored(t *testing.T) {
+	defaultSize := middleware.MaxFormSize
+	middleware.MaxFormSize = 16
+	defer func() { middleware.MaxFormSize = defaultSize }()
+	current := session.NewSession("", nil)
+	request, _ := csrfRequestWithSession("POST", "/photos", "title=A+long+title&_token="+current.Token(), nil, current)
+
+	response := middleware.VerifyCsrfToken{}.Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, 419, response.GetStatus())
+}
+
+func Test_csrf_excluded_uri(t *testing.T) {
+	request, _ := csrfRequest("POST", "/webhooks/stripe", "", nil)
+
+	response := middleware.VerifyCsrfToken{Except: []string{"webhooks/*"}}.Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusOK, response.GetStatus())
+}
+
+func Test_csrf_without_session(t *testing.T) {
+	app := foundation.NewApp()
+	app.Bind("outcome_html_encoders", mock.HtmlEncoders)
+	app.Bind("default_response_outcome", outcome.Html)
+	request := http.NewRequest(http.Options{App: app, Method: "POST", Url: "/profile"})
+
+	response := middleware.VerifyCsrfToken{}.Handle(request, dummyMiddlewareResponder)
+
+	require.Equal(t, net.StatusInternalServerError, response.GetStatus())
+}
+
+func Test_csrf_field_in_template(t *testing.T) {
+	request, current := csrfRequest("GET", "/profile", "", nil)
+
+	var result string
+	middleware.VerifyCsrfToken{}.Handle(request, func(request inter.Request) inter.Response {
+		var err error
+		result, err = view_helper.ContentByView(
+			csrfView{},
+			func(template *template.Template) (*template.Template, error) { return template, nil },
+			view_helper.TemplateFunctions(request.App()),
+		)
+		require.NoError(t, err)
+		return outcome.Html("")
+	})
+
+	require.Equal(t, `<input type="hidden" name="_token" value="`+current.Token()+`">`, result)
+}
+
+type csrfView struct{}
+
+func (c csrfView) Template() string {
+	return "{{ csrf_field }}"
+}
+
+func csrfRequest(method string, url string, form string, headers map[string]string) (inter.Request, *session.Session) {
+	return csrfRequestWithSession(method, url, form, headers, session.NewSession("", nil))
+}
+
+func csrfRequestWithSession(
+	method string,
+	url string,
+	form string,
+	headers map[string]string,
+	current *session.Session,
+) (inter.Request, *session.Session) {
+	app := foundation.NewApp()
+	app.Bind("outcome_html_encoders", mock.HtmlEncoders)
+	app.Bind("default_response_outcome", outcome.Html)
+	app.Bind("session", current)
+
+	source := httptest.NewRequest(method, url, strings.NewReader(form))
+	if form != "" {
+		source.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	for key, value := range headers {
+		source.Header.Set(key, value)
+	}
+
+	return http.NewRequest(http.Options{App: app, Source: *source}), current
+}
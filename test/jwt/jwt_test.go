@@ -0,0 +1,314 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/auth"
+	"github.com/confetti-framework/foundation/http/jwt"
+	"github.com/confetti-framework/foundation/http/middleware"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"github.com/confetti-framework/foundation/test/mock"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	net "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var hmacKey = jwt.Key{Id: "2021-01", Algorithm: jwt.HS256, Secret: []byte("secret")}
+
+func Test_issue_and_verify_hs256(t *testing.T) {
+	service := jwt.Jwt{Keys: []jwt.Key{hmacKey}}
+
+	token, err := service.Issue(jwt.Claims{"sub": "12"})
+	require.NoError(t, err)
+	claims, err := service.Verify(token)
+
+	require.NoError(t, err)
+	require.Equal(t, "12", claims.Subject())
+}
+
+func Test_issue_and_verify_rs256(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	issuer := jwt.Jwt{Keys: []jwt.Key{{Id: "rsa", Algorithm: jwt.RS256, PrivateKey: private}}}
+	verifier := jwt.Jwt{Keys: []jwt.Key{{Id: "rsa", Algorithm: jwt.RS256, PublicKey: &private.PublicKey}}}
+
+	token, err := issuer.Issue(jwt.Claims{"sub": "12"})
+	require.NoError(t, err)
+	claims, err := verifier.Verify(token)
+
+	require.NoError(t, err)
+	require.Equal(t, "12", claims.Subject())
+}
+
+func Test_issue_and_verify_eddsa(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	issuer := jwt.Jwt{Keys: []jwt.Key{{Id: "ed", Algorithm: jwt.EdDSA, PrivateKey: private}}}
+	verifier := jwt.Jwt{Keys: []jwt.Key{{Id: "ed", Algorithm: jwt.EdDSA, PublicKey: public}}}
+
+	token, err := issuer.Issue(jwt.Claims{"sub": "12"})
+	require.NoError(t, err)
+	_, err = verifier.Verify(token)
+
+	require.NoError(t, err)
+}
+
+func Test_verify_with_changed_payload(t *testing.T) {
+	service := jwt.Jwt{Keys: []jwt.Key{hmacKey}}
+	token, err := service.Issue(jwt.Claims{"sub": "12"})
+	require.NoError(t, err)
+	parts := strings.Split(token, ".")
+	parts[1] = base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"1"}`))
+
+	_, err = service.Verify(strings.Join(parts, "."))
+
+	require.True(t, errors.Is(err, jwt.InvalidSignatureError))
+}
+
+func Test_verify_malformed_token(t *testing.T) {
+	_, err := jwt.Jwt{Keys: []jwt.Key{hmacKey}}.Verify("invalid")
+
+	require.True(t, errors.Is(err, jwt.MalformedTokenError))
+}
+
+func Test_verify_rejects_algorithm_of_other_key(t *testing.T) {
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	// A HS256 token signed with the public key as secret
+	forged := jwt.Jwt{Keys: []jwt.Key{{Id: "ed", Algorithm: jwt.HS256, Secret: public}}}
+	token, err := forged.Issue(jwt.Claims{"sub": "12"})
+	require.NoError(t, err)
+
+	_, err = jwt.Jwt{Keys: []jwt.Key{{Id: "ed", Algorithm: jwt.EdDSA, PublicKey: public}}}.Verify(token)
+
+	require.True(t, errors.Is(err, jwt.InvalidSignatureError))
+}
+
+func Test_verify_rejects_none_algorithm(t *testing.T) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"12"}`))
+
+	_, err := jwt.Jwt{Keys: []jwt.Key{hmacKey}}.Verify(header + "." + payload + ".")
+
+	require.True(t, errors.Is(err, jwt.InvalidSignatureError))
+}
+
+func Test_verify_token_of_rotated_key(t *testing.T) {
+	previous := jwt.Jwt{Keys: []jwt.Key{hmacKey}}
+	token, err := previous.Issue(jwt.Claims{"sub": "12"})
+	require.NoError(t, err)
+	current := jwt.Jwt{
+		Keys:       []jwt.Key{{Id: "2021-02", Algorithm: jwt.HS256, Secret: []byte("new")}, hmacKey},
+		SigningKey: "2021-02",
+	}
+
+	_, err = current.Verify(token)
+	require.NoError(t, err)
+
+	newToken, err := current.Issue(jwt.Claims{"sub": "12"})
+	require.NoError(t, err)
+	_, err = previous.Verify(newToken)
+	require.True(t, errors.Is(err, jwt.InvalidSignatureError))
+}
+
+func Test_verify_expired_token(t *testing.T) {
+	service := jwt.Jwt{Keys: []jwt.Key{hmacKey}}
+	token, err := service.Issue(jwt.Claims{"sub": "12", "exp": time.Now().Add(-time.Minute)})
+	require.NoError(t, err)
+
+	_, err = service.Verify(token)
+
+	require.True(t, errors.Is(err, jwt.ExpiredTokenError))
+}
+
+func Test_verify_expired_token_within_leeway(t *testing.T) {
+	service := jwt.Jwt{Keys: []jwt.Key{hmacKey}, Leeway: 2 * time.Minute}
+	token, err := service.Issue(jwt.Claims{"sub": "12", "exp": time.Now().Add(-time.Minute)})
+	require.NoError(t, err)
+
+	_, err = service.Verify(token)
+
+	require.NoError(t, err)
+}
+
+func Test_verify_token_not_valid_yet(t *testing.T) {
+	service := jwt.Jwt{Keys: []jwt.Key{hmacKey}}
+	token, err := service.Issue(jwt.Claims{"sub": "12", "nbf": time.Now().Add(time.Minute)})
+	require.NoError(t, err)
+
+	_, err = service.Verify(token)
+
+	require.True(t, errors.Is(err, jwt.TokenNotValidYetError))
+}
+
+func Test_verify_issuer_and_audience(t *testing.T) {
+	issuer := jwt.Jwt{Keys: []jwt.Key{hmacKey}, Issuer: "confetti", Audience: "mobile"}
+	token, err := issuer.Issue(jwt.Claims{"sub": "12"})
+	require.NoError(t, err)
+
+	_, err = issuer.Verify(token)
+	require.NoError(t, err)
+
+	_, err = jwt.Jwt{Keys: []jwt.Key{hmacKey}, Issuer: "other"}.Verify(token)
+	require.True(t, errors.Is(err, jwt.InvalidIssuerError))
+
+	_, err = jwt.Jwt{Keys: []jwt.Key{hmacKey}, Audience: "web"}.Verify(token)
+	require.True(t, errors.Is(err, jwt.InvalidAudienceError))
+}
+
+func Test_verify_audience_list(t *testing.T) {
+	service := jwt.Jwt{Keys: []jwt.Key{hmacKey}, Audience: "mobile"}
+	token, err := jwt.Jwt{Keys: []jwt.Key{hmacKey}}.Issue(jwt.Claims{"aud": []string{"web", "mobile"}})
+	require.NoError(t, err)
+
+	_, err = service.Verify(token)
+
+	require.NoError(t, err)
+}
+
+func Test_issue_with_lifetime(t *testing.T) {
+	service := jwt.Jwt{Keys: []jwt.Key{hmacKey}, Lifetime: time.Hour}
+	token, err := service.Issue(jwt.Claims{})
+	require.NoError(t, err)
+
+	claims, err := service.Verify(token)
+	require.NoError(t, err)
+	expiresAt, ok := claims.ExpiresAt()
+
+	require.True(t, ok)
+	require.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, 2*time.Second)
+}
+
+func Test_load_jwks(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	raw := `{"keys":[
+		{"kty":"OKP","crv":"Ed25519","kid":"ed","x":"` + base64.RawURLEncoding.EncodeToString(public) + `"},
+		{"kty":"oct","kid":"hs","k":"c2VjcmV0"},
+		{"kty":"RSA","kid":"enc","use":"enc","n":"AQAB","e":"AQAB"}
+	]}`
+	path := filepath.Join(tempDir(t), "jwks.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(raw), 0600))
+
+	keys, err := jwt.LoadJwks(path)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+
+	token, err := jwt.Jwt{Keys: []jwt.Key{{Id: "ed", Algorithm: jwt.EdDSA, PrivateKey: private}}}.Issue(jwt.Claims{})
+	require.NoError(t, err)
+	_, err = jwt.Jwt{Keys: keys}.Verify(token)
+	require.NoError(t, err)
+
+	token, err = jwt.Jwt{Keys: []jwt.Key{hmacKey}}.Issue(jwt.Claims{})
+	require.NoError(t, err)
+	_, err = jwt.Jwt{Keys: keys}.Verify(token)
+	require.True(t, errors.Is(err, jwt.InvalidSignatureError))
+}
+
+func Test_parse_jwks_skips_unsupported_keys(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	raw := `{"keys":[
+		{"kty":"EC","crv":"P-256","kid":"ec","alg":"ES256","x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU","y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"},
+		{"kty":"OKP","crv":"X25519","kid":"x25519","x":"hSDwCYkwp1R0i33ctD73Wg2_Og0mOBr066SpjqqbTmo"},
+		{"kty":"RSA","kid":"rs512","alg":"RS512","n":"AQAB","e":"AQAB"},
+		{"kty":"OKP","crv":"Ed25519","kid":"ed","alg":"EdDSA","x":"` + base64.RawURLEncoding.EncodeToString(public) + `"}
+	]}`
+
+	keys, err := jwt.ParseJwks([]byte(raw))
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, "ed", keys[0].Id)
+
+	token, err := jwt.Jwt{Keys: []jwt.Key{{Id: "ed", Algorithm: jwt.EdDSA, PrivateKey: private}}}.Issue(jwt.Claims{})
+	require.NoError(t, err)
+	_, err = jwt.Jwt{Keys: keys}.Verify(token)
+	require.NoError(t, err)
+}
+
+func Test_parse_jwks_with_invalid_supported_key(t *testing.T) {
+	_, err := jwt.ParseJwks([]byte(`{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"ed","x":"invalid"}]}`))
+
+	require.True(t, errors.Is(err, jwt.InvalidKeyError))
+}
+
+func Test_load_pem(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	raw := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)})
+	path := filepath.Join(tempDir(t), "private.pem")
+	require.NoError(t, ioutil.WriteFile(path, raw, 0600))
+
+	key, err := jwt.LoadPem("rsa", path)
+
+	require.NoError(t, err)
+	require.Equal(t, jwt.RS256, key.Algorithm)
+	token, err := jwt.Jwt{Keys: []jwt.Key{key}}.Issue(jwt.Claims{})
+	require.NoError(t, err)
+	_, err = jwt.Jwt{Keys: []jwt.Key{{Id: "rsa", Algorithm: jwt.RS256, PublicKey: &private.PublicKey}}}.Verify(token)
+	require.NoError(t, err)
+}
+
+func Test_jwt_guard(t *testing.T) {
+	service := jwt.Jwt{Keys: []jwt.Key{hmacKey}}
+	token, err := service.Issue(jwt.Claims{"sub": "12", "name": "Confetti"})
+	require.NoError(t, err)
+	request := guardRequest(service, token)
+
+	var user auth.User
+	response := middleware.Authenticate("jwt").Handle(request, func(request inter.Request) inter.Response {
+		user = request.(*http.Request).User()
+		return outcome.Html("")
+	})
+
+	require.Equal(t, net.StatusOK, response.GetStatus())
+	require.Equal(t, "12", user.AuthIdentifier())
+	require.Equal(t, "Confetti", user.(auth.GenericUser).Get("name").String())
+}
+
+func Test_jwt_guard_with_expired_token(t *testing.T) {
+	service := jwt.Jwt{Keys: []jwt.Key{hmacKey}}
+	token, err := service.Issue(jwt.Claims{"sub": "12", "exp": time.Now().Add(-time.Minute)})
+	require.NoError(t, err)
+	request := guardRequest(service, token)
+
+	response := middleware.Authenticate("jwt").Handle(request, func(request inter.Request) inter.Response {
+		return outcome.Html("")
+	})
+
+	require.Equal(t, net.StatusUnauthorized, response.GetStatus())
+	require.Equal(t, "Bearer", response.GetHeader("WWW-Authenticate"))
+}
+
+func guardRequest(service jwt.Jwt, token string) inter.Request {
+	app := foundation.NewApp()
+	app.Bind("outcome_html_encoders", mock.HtmlEncoders)
+	app.Bind("default_response_outcome", outcome.Html)
+	app.Bind("auth_guards", auth.Guards{"jwt": auth.JwtGuard{Jwt: service}})
+
+	source := httptest.NewRequest("GET", "/orders", nil)
+	source.Header.Set("Authorization", "Bearer "+token)
+
+	return http.NewRequest(http.Options{App: app, Source: *source})
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "jwt")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return dir
+}
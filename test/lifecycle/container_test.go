@@ -260,3 +260,19 @@ func Test_resolve_from_normal_container_but_not_from_boot(t *testing.T) {
 
 	require.Equal(t, "Cooler", container.Make("application_name"))
 }
+
+func Test_copy_container(t *testing.T) {
+	bootContainer := foundation.NewContainer()
+	bootContainer.Bind("application_name", "Heater")
+	container := foundation.NewContainerByBoot(bootContainer).(*foundation.Container)
+	container.Bind("environment", "testing")
+
+	copied := container.Copy()
+	copied.Bind("environment", "production")
+	container.Bind("locale", "nl")
+
+	require.Equal(t, "Heater", copied.Make("application_name"))
+	require.Equal(t, "production", copied.Make("environment"))
+	require.Equal(t, "testing", container.Make("environment"))
+	require.False(t, copied.Bound("locale"))
+}
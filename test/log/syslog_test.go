@@ -93,6 +93,16 @@ func Test_log_with_string(t *testing.T) {
 	require.Regexp(t, ` \[level severity="info"\] the message string data$`, lines[0][0])
 }
 
+func Test_log_with_percent_in_message(t *testing.T) {
+	setUp()
+	logger := getLogger(testFile, 1)
+
+	logger.LogWith(log_level.INFO, "GET /users/john%20doe %s %d", "string data")
+
+	lines := openAndReadFile(testFile)
+	require.Regexp(t, ` \[level severity="info"\] GET /users/john%20doe %s %d string data$`, lines[0][0])
+}
+
 func Test_log_with_map(t *testing.T) {
 	setUp()
 	logger := getLogger(testFile, 1)
@@ -0,0 +1,46 @@
+package openapi
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/http/openapi"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_operation_ids_are_unique_for_routes_with_the_same_name(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/photos/{photo}", controller).Name("photos.show"),
+		routing.Put("/photos/{photo}", controller).Name("photos.update"),
+		routing.Patch("/photos/{photo}", controller).Name("photos.update"),
+	).Compile()
+
+	document := openapi.Generate(routes.All(), openapi.Info{})
+
+	photo := document.Paths["/photos/{photo}"]
+	require.Equal(t, "photos.show", photo["get"].OperationId)
+	require.Equal(t, "photos.update.put", photo["put"].OperationId)
+	require.Equal(t, "photos.update.patch", photo["patch"].OperationId)
+}
+
+func Test_optional_path_parameter(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/posts/{page?}", controller).Name("posts.index"),
+	).Compile()
+
+	document := openapi.Generate(routes.All(), openapi.Info{})
+
+	require.Len(t, document.Paths, 2)
+	withPage := document.Paths["/posts/{page}"]["get"]
+	require.Equal(t, "posts.index", withPage.OperationId)
+	require.Equal(t, []openapi.Parameter{
+		{Name: "page", In: "path", Required: true, Schema: &openapi.Schema{Type: "string"}},
+	}, withPage.Parameters)
+	withoutPage := document.Paths["/posts"]["get"]
+	require.Equal(t, "posts.index.2", withoutPage.OperationId)
+	require.Empty(t, withoutPage.Parameters)
+}
+
+func controller(_ inter.Request) inter.Response {
+	return nil
+}
@@ -0,0 +1,69 @@
+package openapi
+
+import (
+	"github.com/confetti-framework/foundation/http/openapi"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type timestamps struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type category struct {
+	timestamps
+	Name     string            `json:"name"`
+	Parent   *category         `json:"parent"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Image    []byte            `json:"image"`
+	Score    float64           `json:"score"`
+	Internal string            `json:"-"`
+	private  string
+}
+
+// Documentation has the same name as routing.Documentation
+type Documentation struct {
+	Title string `json:"title"`
+}
+
+func Test_schema_of_structs_with_the_same_name(t *testing.T) {
+	schemas := openapi.NewSchemas()
+
+	first := schemas.Of(routing.Documentation{})
+	second := schemas.Of(Documentation{})
+
+	require.Equal(t, &openapi.Schema{Ref: "#/components/schemas/Documentation"}, first)
+	require.Equal(t, &openapi.Schema{Ref: "#/components/schemas/openapi.Documentation"}, second)
+	require.Contains(t, schemas.Components["Documentation"].Properties, "Summary")
+	require.Contains(t, schemas.Components["openapi.Documentation"].Properties, "title")
+	require.Equal(t, first, schemas.Of(&routing.Documentation{}))
+}
+
+func Test_schema_of_scalars(t *testing.T) {
+	schemas := openapi.NewSchemas()
+
+	require.Equal(t, &openapi.Schema{Type: "string"}, schemas.Of(""))
+	require.Equal(t, &openapi.Schema{Type: "boolean"}, schemas.Of(true))
+	require.Equal(t, &openapi.Schema{Type: "integer", Format: "int64"}, schemas.Of(int64(1)))
+	require.Equal(t, &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "integer"}}, schemas.Of([]int{}))
+	require.Equal(t, &openapi.Schema{}, schemas.Of([]interface{}{}).Items)
+}
+
+func Test_schema_of_struct_as_component(t *testing.T) {
+	schemas := openapi.NewSchemas()
+
+	schema := schemas.Of(&category{})
+
+	require.Equal(t, &openapi.Schema{Ref: "#/components/schemas/category"}, schema)
+	component := schemas.Components["category"]
+	require.Equal(t, "object", component.Type)
+	require.Equal(t, []string{"created_at", "name", "image", "score"}, component.Required)
+	require.Equal(t, &openapi.Schema{Type: "string", Format: "date-time"}, component.Properties["created_at"])
+	require.Equal(t, &openapi.Schema{Ref: "#/components/schemas/category"}, component.Properties["parent"])
+	require.Equal(t, &openapi.Schema{Type: "string", ContentEncoding: "base64"}, component.Properties["image"])
+	require.Equal(t, &openapi.Schema{Type: "string"}, component.Properties["labels"].AdditionalProperties)
+	require.NotContains(t, component.Properties, "Internal")
+	require.NotContains(t, component.Properties, "private")
+}
@@ -0,0 +1,76 @@
+package request
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/method"
+	"github.com/confetti-framework/foundation/http/middleware"
+	"github.com/confetti-framework/support"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_request_content_type_xml(t *testing.T) {
+	request := requestWithBody("application/xml; charset=UTF-8", `<user><name>Janet</name></user>`)
+
+	middleware.RequestBodyDecoder{}.Handle(request, emptyController)
+
+	require.Equal(t, "Janet", request.Content("name").String())
+}
+
+func Test_request_content_type_yaml(t *testing.T) {
+	request := requestWithBody("application/yaml", "name: Janet")
+
+	middleware.RequestBodyDecoder{}.Handle(request, emptyController)
+
+	require.Equal(t, "Janet", request.Content("name").String())
+}
+
+func Test_request_content_type_with_structured_suffix(t *testing.T) {
+	request := requestWithBody("application/vnd.api+json", `{"name":"Janet"}`)
+
+	middleware.RequestBodyDecoder{}.Handle(request, emptyController)
+
+	require.Equal(t, "Janet", request.Content("name").String())
+}
+
+func Test_request_content_type_text(t *testing.T) {
+	request := requestWithBody("text/plain", "Janet")
+
+	middleware.RequestBodyDecoder{}.Handle(request, emptyController)
+
+	require.Equal(t, "Janet", request.Content().String())
+}
+
+func Test_request_content_type_unknown(t *testing.T) {
+	request := requestWithBody("application/unknown", "Janet")
+
+	middleware.RequestBodyDecoder{}.Handle(request, emptyController)
+
+	_, err := request.ContentE()
+	require.ErrorIs(t, err, http.NoRequestBodyDecoderFoundError)
+}
+
+func Test_request_content_type_from_custom_decoders(t *testing.T) {
+	request := requestWithBody("text/csv", "Janet,Bob")
+	request.App().Bind("request_body_decoders", middleware.BodyDecoders{
+		"text/csv": func(request inter.Request) support.Value {
+			return support.NewValue(request.Body()).Split(",").First()
+		},
+	})
+
+	middleware.RequestBodyDecoder{}.Handle(request, emptyController)
+
+	require.Equal(t, "Janet", request.Content().String())
+}
+
+func requestWithBody(contentType string, content string) inter.Request {
+	return http.NewRequest(http.Options{
+		App:     foundation.NewApp(),
+		Method:  method.Post,
+		Url:     "/users",
+		Header:  map[string][]string{"Content-Type": {contentType}},
+		Content: content,
+	})
+}
@@ -89,6 +89,27 @@ func Test_get_url(t *testing.T) {
 	require.Equal(t, "https://api.confetti-framework.com/user/1432?test=123", request.FullUrl())
 }
 
+func Test_get_url_of_helper_with_host_and_scheme(t *testing.T) {
+	request := fakeRequestWithJsonBody().(*http.Request)
+
+	require.Equal(t, "api.confetti-framework.com", request.Host())
+	require.True(t, request.IsSecure())
+	require.Equal(t, "https://api.confetti-framework.com/user/2432", request.Url())
+}
+
+func Test_get_url_with_absolute_url(t *testing.T) {
+	request := http.NewRequest(http.Options{
+		Method: method.Get,
+		Url:    "https://api.confetti-framework.com/user/1432?test=123",
+	})
+
+	require.Equal(t, "GET", request.Method())
+	require.True(t, http_helper.IsMethod(request, "GET"))
+	require.Equal(t, "/user/1432", request.Path())
+	require.Equal(t, "https://api.confetti-framework.com/user/1432", request.Url())
+	require.Equal(t, "https://api.confetti-framework.com/user/1432?test=123", request.FullUrl())
+}
+
 func Test_all_contents(t *testing.T) {
 	request := fakeRequestWithForm()
 	request.App().Bind(inter.RequestBodyDecoder, encoder.RequestWithFormToValue)
@@ -0,0 +1,179 @@
+package request
+
+import (
+	"crypto/tls"
+	"github.com/confetti-framework/foundation"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/stretchr/testify/require"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_ip_without_proxy(t *testing.T) {
+	request := requestFromProxy("203.0.113.9:5555", map[string]string{
+		"X-Forwarded-For": "198.51.100.1",
+	}, nil)
+
+	require.Equal(t, "203.0.113.9", request.Ip())
+	require.Equal(t, []string{"203.0.113.9"}, request.Ips())
+}
+
+func Test_ip_from_untrusted_proxy_ignores_header(t *testing.T) {
+	request := requestFromProxy("203.0.113.9:5555", map[string]string{
+		"X-Forwarded-For": "198.51.100.1",
+	}, []interface{}{"10.0.0.0/8"})
+
+	require.Equal(t, "203.0.113.9", request.Ip())
+}
+
+func Test_ip_from_trusted_proxy(t *testing.T) {
+	request := requestFromProxy("10.0.0.2:5555", map[string]string{
+		"X-Forwarded-For": "198.51.100.1",
+	}, []interface{}{"10.0.0.0/8"})
+
+	require.Equal(t, "198.51.100.1", request.Ip())
+}
+
+func Test_ip_skips_spoofed_addresses_before_untrusted_hop(t *testing.T) {
+	request := requestFromProxy("10.0.0.2:5555", map[string]string{
+		"X-Forwarded-For": "1.1.1.1, 198.51.100.1, 10.0.0.3",
+	}, []interface{}{"10.0.0.0/8"})
+
+	require.Equal(t, "198.51.100.1", request.Ip())
+	require.Equal(t, []string{"198.51.100.1", "1.1.1.1"}, request.Ips())
+}
+
+func Test_ip_from_trusted_proxy_by_single_address(t *testing.T) {
+	request := requestFromProxy("10.0.0.2:5555", map[string]string{
+		"X-Forwarded-For": "198.51.100.1",
+	}, []interface{}{"10.0.0.2"})
+
+	require.Equal(t, "198.51.100.1", request.Ip())
+}
+
+func Test_ip_from_forwarded_header(t *testing.T) {
+	request := requestFromProxy("10.0.0.2:5555", map[string]string{
+		"Forwarded":       `for="[2001:db8:cafe::17]:4711";proto=https;host=example.com`,
+		"X-Forwarded-For": "198.51.100.1",
+	}, []interface{}{"*"})
+
+	require.Equal(t, "2001:db8:cafe::17", request.Ip())
+	require.Equal(t, "https", request.Scheme())
+	require.Equal(t, "example.com", request.Host())
+}
+
+func Test_scheme_without_proxy(t *testing.T) {
+	request := requestFromProxy("203.0.113.9:5555", map[string]string{
+		"X-Forwarded-Proto": "https",
+	}, nil)
+
+	require.Equal(t, "http", request.Scheme())
+	require.False(t, request.IsSecure())
+}
+
+func Test_scheme_with_tls(t *testing.T) {
+	request := requestFromProxy("203.0.113.9:5555", nil, nil)
+	source := request.Source()
+	source.TLS = &tls.ConnectionState{}
+	request = http.NewRequest(http.Options{Source: source}).(*http.Request)
+
+	require.Equal(t, "https", request.Scheme())
+	require.True(t, request.IsSecure())
+}
+
+func Test_scheme_host_and_port_from_trusted_proxy(t *testing.T) {
+	request := requestFromProxy("10.0.0.2:5555", map[string]string{
+		"X-Forwarded-Proto": "https",
+		"X-Forwarded-Host":  "confetti-framework.com",
+		"X-Forwarded-Port":  "8443",
+	}, []interface{}{"10.0.0.0/8"})
+
+	require.Equal(t, "https", request.Scheme())
+	require.True(t, request.IsSecure())
+	require.Equal(t, "confetti-framework.com", request.Host())
+	require.Equal(t, 8443, request.Port())
+	require.Equal(t, "https://confetti-framework.com:8443/users", request.Url())
+	require.Equal(t, "https://confetti-framework.com:8443/users?page=2", request.FullUrl())
+}
+
+func Test_host_sent_by_client_before_value_of_trusted_proxy(t *testing.T) {
+	request := requestFromProxy("10.0.0.2:5555", map[string]string{
+		"X-Forwarded-For":   "198.51.100.1",
+		"X-Forwarded-Proto": "http, https",
+		"X-Forwarded-Host":  "evil.com, confetti-framework.com",
+		"X-Forwarded-Port":  "80, 443",
+	}, []interface{}{"10.0.0.0/8"})
+
+	require.Equal(t, "confetti-framework.com", request.Host())
+	require.True(t, request.IsSecure())
+	require.Equal(t, 443, request.Port())
+	require.Equal(t, "https://confetti-framework.com/users", request.Url())
+}
+
+func Test_host_sent_by_client_in_separate_header(t *testing.T) {
+	app := foundation.NewApp()
+	app.Bind("config.App.TrustedProxies", []interface{}{"10.0.0.0/8"})
+	source := httptest.NewRequest("GET", "/users", nil)
+	source.RemoteAddr = "10.0.0.2:5555"
+	source.Header.Add("X-Forwarded-For", "198.51.100.1")
+	source.Header.Add("X-Forwarded-Host", "evil.com")
+	source.Header.Add("X-Forwarded-Host", "confetti-framework.com")
+	request := http.NewRequest(http.Options{App: app, Source: *source})
+
+	require.Equal(t, "confetti-framework.com", request.(*http.Request).Host())
+}
+
+func Test_host_of_outer_trusted_proxy(t *testing.T) {
+	request := requestFromProxy("10.0.0.2:5555", map[string]string{
+		"X-Forwarded-For":  "198.51.100.1, 10.0.0.3",
+		"X-Forwarded-Host": "evil.com, confetti-framework.com, internal.local",
+	}, []interface{}{"10.0.0.0/8"})
+
+	require.Equal(t, "confetti-framework.com", request.Host())
+}
+
+func Test_forwarded_element_sent_by_client(t *testing.T) {
+	request := requestFromProxy("10.0.0.2:5555", map[string]string{
+		"Forwarded": `for=1.1.1.1;host=evil.com;proto=http, for=198.51.100.1;host=confetti-framework.com;proto=https`,
+	}, []interface{}{"10.0.0.0/8"})
+
+	require.Equal(t, "198.51.100.1", request.Ip())
+	require.Equal(t, "confetti-framework.com", request.Host())
+	require.Equal(t, "https", request.Scheme())
+}
+
+func Test_default_port_by_scheme(t *testing.T) {
+	request := requestFromProxy("10.0.0.2:5555", map[string]string{
+		"X-Forwarded-Proto": "https",
+		"X-Forwarded-Host":  "confetti-framework.com",
+	}, []interface{}{"10.0.0.0/8"})
+
+	require.Equal(t, 443, request.Port())
+	require.Equal(t, "https://confetti-framework.com/users", request.Url())
+}
+
+func Test_host_and_port_without_proxy(t *testing.T) {
+	request := requestFromProxy("203.0.113.9:5555", map[string]string{
+		"X-Forwarded-Host": "evil.com",
+	}, nil)
+
+	require.Equal(t, "example.com", request.Host())
+	require.Equal(t, 8080, request.Port())
+	require.Equal(t, "http://example.com:8080/users", request.Url())
+}
+
+func requestFromProxy(remoteAddr string, headers map[string]string, proxies []interface{}) *http.Request {
+	app := foundation.NewApp()
+	if proxies != nil {
+		app.Bind("config.App.TrustedProxies", proxies)
+	}
+
+	source := httptest.NewRequest("GET", "/users?page=2", nil)
+	source.Host = "example.com:8080"
+	source.RemoteAddr = remoteAddr
+	for key, value := range headers {
+		source.Header.Set(key, value)
+	}
+
+	return http.NewRequest(http.Options{App: app, Source: *source}).(*http.Request)
+}
@@ -0,0 +1,88 @@
+package routing
+
+import (
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/method"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_first_registered_route_takes_precedence(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/users/{id}", emptyController()).Name("ById"),
+		routing.Get("/users/active", emptyController()).Name("Active"),
+	)
+
+	route := routes.Match(newRequest(http.Options{Method: method.Get, Url: "/users/active"}))
+
+	require.Equal(t, "ById", route.Name())
+}
+
+func Test_static_route_registered_first_takes_precedence(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/users/active", emptyController()).Name("Active"),
+		routing.Get("/users/{id}", emptyController()).Name("ById"),
+	)
+
+	route := routes.Match(newRequest(http.Options{Method: method.Get, Url: "/users/active"}))
+
+	require.Equal(t, "Active", route.Name())
+}
+
+func Test_routes_with_shared_static_prefix(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/user", emptyController()).Name("User"),
+		routing.Get("/users", emptyController()).Name("Users"),
+		routing.Get("/user_roles", emptyController()).Name("UserRoles"),
+		routing.Get("/us", emptyController()).Name("Us"),
+	)
+
+	for url, name := range map[string]string{"/user": "User", "/users": "Users", "/user_roles": "UserRoles", "/us": "Us"} {
+		route := routes.Match(newRequest(http.Options{Method: method.Get, Url: url}))
+		require.Equal(t, name, route.Name(), url)
+	}
+}
+
+func Test_parameter_crossing_static_prefix(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/files/{path}", emptyController()).Where("path", ".*").Name("Files"),
+	)
+
+	request := newRequest(http.Options{Method: method.Get, Url: "/files/images/logo.png"})
+	route := routes.Match(request)
+
+	require.Equal(t, "Files", route.Name())
+	require.Equal(t, "images/logo.png", request.Parameter("path").String())
+}
+
+func Test_route_pushed_after_first_match(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/users", emptyController()).Name("Users"),
+	)
+	routes.Match(newRequest(http.Options{Method: method.Get, Url: "/users"}))
+
+	routes.Merge(routing.Get("/roles", emptyController()).Name("Roles"))
+	route := routes.Match(newRequest(http.Options{Method: method.Get, Url: "/roles/"}))
+
+	require.Equal(t, "Roles", route.Name())
+}
+
+func Test_compile_routes_at_boot(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/users", emptyController()).Name("Users"),
+	).Compile()
+
+	require.Equal(t, "/users{allow_slash:\\/?}", routes.All()[0].Uri())
+}
+
+func Test_route_with_capture_group_in_constraint(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/users/{id}", emptyController()).Where("id", "([0-9]+)"),
+	)
+
+	request := newRequest(http.Options{Method: method.Get, Url: "/users/12"})
+	response := routes.Match(request).Controller()(request)
+
+	require.Contains(t, response.GetContent().(error).Error(), "Only non-capturing groups are accepted")
+}
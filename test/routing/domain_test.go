@@ -2,6 +2,7 @@ package routing
 
 import (
 	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation"
 	"github.com/confetti-framework/foundation/http"
 	"github.com/confetti-framework/foundation/http/method"
 	"github.com/confetti-framework/foundation/http/outcome"
@@ -67,3 +68,50 @@ func Test_Parameter_from_domain(t *testing.T) {
 	body := response.GetBody()
 	require.Equal(t, "klaas", body)
 }
+
+func Test_domain_parameter_from_request(t *testing.T) {
+	routes := routing.Domain(
+		"{tenant}.example.com",
+		routing.Get("/users/{user}", func(request inter.Request) inter.Response {
+			httpRequest := request.(*http.Request)
+			_, err := httpRequest.DomainE("user")
+			require.NotNil(t, err)
+			return outcome.Html(httpRequest.Domain("tenant").String() + " " + request.Parameter("user").String())
+		}),
+	)
+
+	request := newRequest(http.Options{Method: method.Get, Url: "/users/12", Host: "acme.example.com"})
+	response := routes.Match(request).Controller()(request)
+	response.SetApp(request.App())
+
+	require.Equal(t, "acme 12", response.GetBody())
+	require.Equal(t, "acme", request.Parameter("tenant").String())
+}
+
+func Test_domain_group_with_constraint(t *testing.T) {
+	routes := routing.Group(
+		routing.Domain(
+			"{tenant}.example.com",
+			routing.Get("/users", emptyController()).Name("tenant"),
+		).Where("tenant", "[a-z]+"),
+		routing.Get("/users", emptyController()).Name("other"),
+	)
+
+	tenant := routes.Match(newRequest(http.Options{Method: method.Get, Url: "/users", Host: "acme.example.com"}))
+	other := routes.Match(newRequest(http.Options{Method: method.Get, Url: "/users", Host: "123.example.com"}))
+
+	require.True(t, tenant.Named("tenant"))
+	require.True(t, other.Named("other"))
+}
+
+func Test_url_by_name_with_domain_group(t *testing.T) {
+	app := foundation.NewApp()
+	app.Singleton("routes", routing.Domain(
+		"{tenant}.example.com",
+		routing.Get("/users/{user}", emptyController()).Name("user"),
+	))
+
+	url := outcome.UrlByName(app, "user", outcome.Parameters{"tenant": "acme", "user": 12})
+
+	require.Equal(t, "https://acme.example.com/users/12", url)
+}
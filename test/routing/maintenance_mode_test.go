@@ -0,0 +1,72 @@
+package routing
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/encoder"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/maintenance"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/confetti-framework/foundation/test/mock"
+	"github.com/stretchr/testify/require"
+	net "net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+// unauthorizedMiddleware fails every request, as authentication would
+// without a session while the application is down
+type unauthorizedMiddleware struct {
+	ran *bool
+}
+
+func (u unauthorizedMiddleware) Handle(request inter.Request, next inter.Next) inter.Response {
+	*u.ran = true
+	return outcome.Html("unauthorized").Status(net.StatusUnauthorized)
+}
+
+func Test_maintenance_mode_before_route_middlewares(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	ran := false
+	app := maintenanceKernelApp(t)
+	app.Singleton("routes", routing.Group(
+		routing.Get("/roles", func(request inter.Request) inter.Response {
+			return outcome.Html("roles")
+		}),
+	).Middleware(unauthorizedMiddleware{ran: &ran}))
+
+	http.HandleHttpKernel(app, recorder, httptest.NewRequest(net.MethodGet, "/roles", nil))
+
+	require.Equal(t, net.StatusServiceUnavailable, recorder.Code)
+	require.Equal(t, "60", recorder.Header().Get("Retry-After"))
+	require.Equal(t, "Service unavailable due to maintenance", recorder.Body.String())
+	require.False(t, ran)
+}
+
+func Test_maintenance_mode_before_global_middlewares(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	ran := false
+	app := maintenanceKernelApp(t)
+	app.Bind("global_middlewares", []inter.HttpMiddleware{unauthorizedMiddleware{ran: &ran}})
+	app.Singleton("routes", routing.Get("/roles", func(request inter.Request) inter.Response {
+		return outcome.Html("roles")
+	}))
+
+	http.HandleHttpKernel(app, recorder, httptest.NewRequest(net.MethodGet, "/roles", nil))
+
+	require.Equal(t, net.StatusServiceUnavailable, recorder.Code)
+	require.False(t, ran)
+}
+
+func maintenanceKernelApp(t *testing.T) inter.App {
+	path := filepath.Join(t.TempDir(), "down")
+	require.NoError(t, maintenance.Down(path, maintenance.Mode{Retry: 60}))
+
+	app := newKernelApp()
+	app.Bind("outcome_html_encoders", append([]inter.Encoder{encoder.ErrorsToHtml{}}, mock.HtmlEncoders...))
+	app.Bind("default_response_outcome", outcome.Html)
+	app.Bind("maintenance_path", path)
+
+	return app
+}
@@ -0,0 +1,119 @@
+package routing
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/method"
+	"github.com/confetti-framework/foundation/http/middleware"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/stretchr/testify/require"
+	net "net/http"
+	"testing"
+)
+
+func Test_method_override_by_form_field(t *testing.T) {
+	request := newRequest(http.Options{
+		Method:  method.Post,
+		Url:     "/photos/12",
+		Header:  net.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+		Content: "_method=DELETE&title=Sunset",
+	})
+
+	response := handleWithMethodOverride(request)
+
+	require.Equal(t, "delete Sunset", response.GetBody())
+}
+
+func Test_method_override_by_header(t *testing.T) {
+	request := newRequest(http.Options{
+		Method: method.Post,
+		Url:    "/photos/12",
+		Header: net.Header{"X-Http-Method-Override": {"put"}},
+	})
+
+	response := handleWithMethodOverride(request)
+
+	require.Equal(t, "put", response.GetBody())
+}
+
+func Test_method_override_ignores_other_methods(t *testing.T) {
+	request := newRequest(http.Options{
+		Method: method.Post,
+		Url:    "/photos/12",
+		Header: net.Header{"X-Http-Method-Override": {"GET"}},
+	})
+
+	response := handleWithMethodOverride(request)
+
+	require.Equal(t, "post ", response.GetBody())
+}
+
+func Test_method_override_only_on_post(t *testing.T) {
+	request := newRequest(http.Options{
+		Method: method.Get,
+		Url:    "/photos/12",
+		Header: net.Header{"X-Http-Method-Override": {"DELETE"}},
+	})
+
+	response := handleWithMethodOverride(request)
+
+	require.Equal(t, "get ", response.GetBody())
+}
+
+func Test_method_override_ignores_form_larger_than_limit(t *testing.T) {
+	defaultSize := middleware.MaxFormSize
+	middleware.MaxFormSize = 16
+	defer func() { middleware.MaxFormSize = defaultSize }()
+	request := newRequest(http.Options{
+		Method:  method.Post,
+		Url:     "/photos/12",
+		Header:  net.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+		Content: "title=A+long+title+of+a+sunset&_method=DELETE",
+	})
+
+	response := handleWithMethodOverride(request)
+
+	require.Equal(t, "post A long title of a sunset", response.GetBody())
+}
+
+func Test_form_body_of_get_request_is_not_decoded(t *testing.T) {
+	request := newRequest(http.Options{
+		Method:  method.Get,
+		Url:     "/photos/12",
+		Header:  net.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+		Content: "title=Sunset",
+	})
+
+	response := handleWithMethodOverride(request)
+
+	require.Equal(t, "get ", response.GetBody())
+}
+
+func handleWithMethodOverride(request inter.Request) inter.Response {
+	request.App().Bind("global_middlewares", []inter.HttpMiddleware{middleware.MethodOverride{}})
+	request.App().Singleton("routes", routing.Group(
+		routing.Get("/photos/{id}", func(request inter.Request) inter.Response {
+			return outcome.Html("get " + formTitle(request))
+		}),
+		routing.Post("/photos/{id}", func(request inter.Request) inter.Response {
+			return outcome.Html("post " + formTitle(request))
+		}),
+		routing.Put("/photos/{id}", func(request inter.Request) inter.Response {
+			return outcome.Html("put")
+		}),
+		routing.Delete("/photos/{id}", func(request inter.Request) inter.Response {
+			return outcome.Html("delete " + request.Content("title").String())
+		}),
+	).Middleware(middleware.RequestBodyDecoder{}))
+
+	return http.Kernel{}.Handle(request)
+}
+
+func formTitle(request inter.Request) string {
+	title, err := request.ContentE("title")
+	if err != nil {
+		return ""
+	}
+	return title.String()
+}
@@ -0,0 +1,152 @@
+package routing
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/method"
+	"github.com/confetti-framework/foundation/http/middleware"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_middleware_group_by_alias(t *testing.T) {
+	request := newRequest(http.Options{Method: method.Get, Url: "/roles"})
+	request.App().Bind("middleware_groups", middleware.Groups{
+		"web": {MockedMiddleware1{}, MockedMiddleware2{}},
+	})
+	request.App().Singleton("routes", routing.Group(
+		routing.Get("/roles", bodyController),
+	).Middleware(middleware.Alias("web")))
+
+	response := http.Kernel{}.Handle(request)
+
+	require.Equal(t, "1 -> 2 ->  <- 2 <- 1", response.GetBody())
+}
+
+func Test_middleware_alias_from_container(t *testing.T) {
+	request := newRequest(http.Options{Method: method.Get, Url: "/roles"})
+	request.App().Bind("middleware_aliases", middleware.Aliases{"third": MockedMiddleware3{}})
+	request.App().Bind("middleware_groups", middleware.Groups{
+		"web": {MockedMiddleware1{}, middleware.Alias("third")},
+	})
+	request.App().Singleton("routes", routing.Group(
+		routing.Get("/roles", bodyController),
+	).Middleware(middleware.Alias("web"), MockedMiddleware2{}))
+
+	response := http.Kernel{}.Handle(request)
+
+	require.Equal(t, "1 -> 3 -> 2 ->  <- 2 <- 3 <- 1", response.GetBody())
+}
+
+func Test_without_middleware_of_group(t *testing.T) {
+	request := newRequest(http.Options{Method: method.Get, Url: "/roles"})
+	request.App().Bind("middleware_groups", middleware.Groups{
+		"web": {MockedMiddleware1{}, MockedMiddleware2{}},
+	})
+	request.App().Singleton("routes", routing.Group(
+		routing.Get("/roles", bodyController).WithoutMiddleware(MockedMiddleware1{}),
+	).Middleware(middleware.Alias("web")))
+
+	response := http.Kernel{}.Handle(request)
+
+	require.Equal(t, "2 ->  <- 2", response.GetBody())
+}
+
+func Test_without_middleware_compares_string_middleware_by_type(t *testing.T) {
+	request := newRequest(http.Options{Method: method.Get, Url: "/roles"})
+	request.App().Singleton("routes", routing.Group(
+		routing.Get("/roles", bodyController).WithoutMiddleware(middleware.Can("")),
+	).Middleware(MockedMiddleware1{}, middleware.Can("update")))
+
+	response := http.Kernel{}.Handle(request)
+
+	require.Equal(t, "1 ->  <- 1", response.GetBody())
+}
+
+func Test_middleware_with_unknown_alias(t *testing.T) {
+	app := foundation.NewApp()
+
+	require.PanicsWithError(t, "no group or alias 'unknown' found: middleware not found", func() {
+		middleware.Resolve(app, []inter.HttpMiddleware{middleware.Alias("unknown")}, nil)
+	})
+}
+
+func Test_middleware_alias_refers_to_itself(t *testing.T) {
+	app := foundation.NewApp()
+	app.Bind("middleware_groups", middleware.Groups{"web": {middleware.Alias("web")}})
+
+	defer func() {
+		require.True(t, errors.Is(recover().(error), middleware.MiddlewareNotFoundError))
+	}()
+	middleware.Resolve(app, []inter.HttpMiddleware{middleware.Alias("web")}, nil)
+}
+
+func Test_middleware_sorted_by_priority(t *testing.T) {
+	priority := []inter.HttpMiddleware{MockedMiddleware1{}, MockedMiddleware2{}}
+
+	result := middleware.SortByPriority(
+		[]inter.HttpMiddleware{MockedMiddleware2{}, MockedMiddleware3{}, MockedMiddleware1{}},
+		priority,
+	)
+
+	require.Equal(t, []inter.HttpMiddleware{MockedMiddleware1{}, MockedMiddleware3{}, MockedMiddleware2{}}, result)
+}
+
+func Test_middleware_priority_from_container(t *testing.T) {
+	request := newRequest(http.Options{Method: method.Get, Url: "/roles"})
+	request.App().Bind("middleware_priority", []inter.HttpMiddleware{MockedMiddleware1{}, MockedMiddleware2{}})
+	request.App().Singleton("routes", routing.Group(
+		routing.Group(
+			routing.Get("/roles", bodyController),
+		).Middleware(MockedMiddleware1{}),
+	).Middleware(MockedMiddleware2{}, MockedMiddleware3{}))
+
+	response := http.Kernel{}.Handle(request)
+
+	require.Equal(t, "1 -> 3 -> 2 ->  <- 2 <- 3 <- 1", response.GetBody())
+}
+
+func Test_default_priority_starts_session_before_authentication(t *testing.T) {
+	result := middleware.SortByPriority(
+		[]inter.HttpMiddleware{middleware.Authenticate("web"), middleware.StartSession{}},
+		middleware.DefaultPriority,
+	)
+
+	require.Equal(t, []inter.HttpMiddleware{middleware.StartSession{}, middleware.Authenticate("web")}, result)
+}
+
+func Test_global_middleware_by_alias(t *testing.T) {
+	request := newRequest(http.Options{Method: method.Get, Url: "/roles"})
+	request.App().Bind("middleware_groups", middleware.Groups{"global": {MockedMiddleware1{}}})
+	request.App().Bind("global_middlewares", []inter.HttpMiddleware{middleware.Alias("global")})
+	request.App().Singleton("routes", routing.NewRouteCollection(
+		routing.Get("/roles", bodyController).Middleware(MockedMiddleware2{}),
+	))
+
+	response := http.Kernel{}.Handle(request)
+
+	require.Equal(t, "1 -> 2 ->  <- 2 <- 1", response.GetBody())
+}
+
+func Test_pipeline_does_not_change_order_of_middlewares(t *testing.T) {
+	request := newRequest(http.Options{Method: method.Get, Url: "/roles"})
+	middlewares := []inter.HttpMiddleware{middleware.RequestID{}, middleware.MethodOverride{}}
+
+	middleware.NewPipeline(request.App()).Send(request).Through(middlewares).Then(bodyController)
+
+	require.Equal(t, []inter.HttpMiddleware{middleware.RequestID{}, middleware.MethodOverride{}}, middlewares)
+}
+
+func Test_middleware_name(t *testing.T) {
+	require.Equal(t, "middleware.Authenticate", middleware.Name(middleware.Authenticate("api")))
+	require.Equal(t, "middleware.Alias(web)", middleware.Name(middleware.Alias("web")))
+	require.Equal(t, "middleware.StartSession", middleware.Name(middleware.StartSession{}))
+}
+
+func bodyController(request inter.Request) inter.Response {
+	return outcome.Html(request.Body())
+}
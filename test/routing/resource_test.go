@@ -0,0 +1,147 @@
+package routing
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/method"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type photoController struct{}
+
+func (p photoController) Index(_ inter.Request) inter.Response {
+	return outcome.Html("index")
+}
+
+func (p photoController) Create(_ inter.Request) inter.Response {
+	return outcome.Html("create")
+}
+
+func (p photoController) Store(_ inter.Request) inter.Response {
+	return outcome.Html("store")
+}
+
+func (p photoController) Show(request inter.Request) inter.Response {
+	return outcome.Html("show " + request.Parameter("photo").String())
+}
+
+func (p photoController) Edit(request inter.Request) inter.Response {
+	return outcome.Html("edit " + request.Parameter("photo").String())
+}
+
+func (p photoController) Update(request inter.Request) inter.Response {
+	return outcome.Html("update " + request.Parameter("photo").String())
+}
+
+func (p photoController) Destroy(request inter.Request) inter.Response {
+	return outcome.Html("destroy " + request.Parameter("photo").String())
+}
+
+type commentController struct{}
+
+func (c commentController) Show(request inter.Request) inter.Response {
+	return outcome.Html(request.Parameter("photo").String() + " " + request.Parameter("comment").String())
+}
+
+func Test_resource_routes(t *testing.T) {
+	routes := routing.Resource("photos", photoController{})
+
+	require.Equal(t, "index", callResource(routes, method.Get, "/photos"))
+	require.Equal(t, "create", callResource(routes, method.Get, "/photos/create"))
+	require.Equal(t, "store", callResource(routes, method.Post, "/photos"))
+	require.Equal(t, "show 12", callResource(routes, method.Get, "/photos/12"))
+	require.Equal(t, "edit 12", callResource(routes, method.Get, "/photos/12/edit"))
+	require.Equal(t, "update 12", callResource(routes, method.Put, "/photos/12"))
+	require.Equal(t, "update 12", callResource(routes, method.Patch, "/photos/12"))
+	require.Equal(t, "destroy 12", callResource(routes, method.Delete, "/photos/12"))
+}
+
+func Test_resource_route_names(t *testing.T) {
+	routes := routing.Resource("photos", photoController{})
+
+	for name, uri := range map[string]string{
+		"photos.index":   "/photos",
+		"photos.create":  "/photos/create",
+		"photos.store":   "/photos",
+		"photos.show":    "/photos/{photo}",
+		"photos.edit":    "/photos/{photo}/edit",
+		"photos.update":  "/photos/{photo}",
+		"photos.destroy": "/photos/{photo}",
+	} {
+		route, err := outcome.RouteByName(routes, name)
+		require.Nil(t, err)
+		require.Equal(t, uri, route.Uri())
+	}
+	require.Empty(t, routes.Validate())
+}
+
+func Test_resource_names_with_group_prefix(t *testing.T) {
+	routes := routing.Group(
+		routing.Resource("photos", photoController{}),
+	).Name("admin.")
+
+	_, err := outcome.RouteByName(routes, "admin.photos.index")
+	require.Nil(t, err)
+}
+
+func Test_api_resource_without_create_and_edit(t *testing.T) {
+	routes := routing.ApiResource("photos", photoController{})
+
+	_, err := outcome.RouteByName(routes, "photos.create")
+	require.NotNil(t, err)
+	_, err = outcome.RouteByName(routes, "photos.edit")
+	require.NotNil(t, err)
+	require.Equal(t, "show create", callResource(routes, method.Get, "/photos/create"))
+}
+
+func Test_resource_only(t *testing.T) {
+	routes := routing.Resource("photos", photoController{}).Only("index", "show")
+
+	require.Len(t, routes.All(), 4)
+	_, err := outcome.RouteByName(routes, "photos.store")
+	require.NotNil(t, err)
+	require.Equal(t, "show 12", callResource(routes, method.Get, "/photos/12"))
+}
+
+func Test_resource_except(t *testing.T) {
+	routes := routing.Resource("photos", photoController{}).Except("destroy", "update")
+
+	for _, route := range routes.All() {
+		require.NotEqual(t, "photos.destroy", route.Name())
+		require.NotEqual(t, "photos.update", route.Name())
+	}
+	require.Len(t, routes.All(), 9)
+}
+
+func Test_resource_registers_only_implemented_actions(t *testing.T) {
+	routes := routing.Resource("photos.comments", commentController{})
+
+	require.Len(t, routes.All(), 2)
+	route, err := outcome.RouteByName(routes, "photos.comments.show")
+	require.Nil(t, err)
+	require.Equal(t, "/photos/{photo}/comments/{comment}", route.Uri())
+}
+
+func Test_nested_resource(t *testing.T) {
+	routes := routing.Resource("photos.comments", commentController{})
+
+	require.Equal(t, "12 3", callResource(routes, method.Get, "/photos/12/comments/3"))
+}
+
+func Test_resource_parameter_from_plural(t *testing.T) {
+	routes := routing.Resource("categories", photoController{}).Only("show")
+
+	require.Equal(t, "/categories/{category}", routes.All()[0].Uri())
+}
+
+func callResource(routes inter.RouteCollection, httpMethod string, url string) string {
+	request := newRequest(http.Options{Method: httpMethod, Url: url})
+	route := routes.Match(request)
+	response := route.Controller()(request)
+	response.SetApp(request.App())
+
+	return response.GetBody()
+}
@@ -0,0 +1,36 @@
+package routing
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// Compare booting an application with 500 routes from the route cache with
+// compiling and validating the registered routes.
+
+func Benchmark_boot_500_routes_compiled(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		routes := benchmarkRoutes()
+		b.StartTimer()
+
+		routes.Compile().Validate()
+	}
+}
+
+func Benchmark_boot_500_routes_from_cache(b *testing.B) {
+	path := filepath.Join(b.TempDir(), "routes.json")
+	if err := benchmarkRoutes().WriteCache(path); err != nil {
+		b.Fatal(err)
+	}
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		routes := benchmarkRoutes()
+		b.StartTimer()
+
+		if err := routes.LoadCache(path); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
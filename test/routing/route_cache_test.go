@@ -0,0 +1,194 @@
+package routing
+
+import (
+	"bytes"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/method"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/confetti-framework/foundation/providers"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func cachedUser(request inter.Request) inter.Response {
+	return outcome.Html("user " + request.Parameter("id").String())
+}
+
+func cachedTenant(request inter.Request) inter.Response {
+	return outcome.Html("tenant " + request.Parameter("tenant").String())
+}
+
+func cachedRoutes() *routing.RouteCollection {
+	return routing.Group(
+		routing.Group(
+			routing.Get("/users/{id}", cachedUser).Name("user"),
+		).Prefix("/api").Where("id", "[0-9]+"),
+		routing.Get("/dashboard", cachedTenant).Domain("{tenant}.example.com").Name("tenant"),
+	)
+}
+
+func Test_load_route_cache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	require.Nil(t, cachedRoutes().WriteCache(path))
+
+	routes := cachedRoutes()
+	require.Nil(t, routes.LoadCache(path))
+
+	require.Equal(t, "user 12", callRoute(routes, http.Options{Method: method.Get, Url: "/api/users/12"}))
+	require.Equal(t, "tenant acme", callRoute(routes, http.Options{Method: method.Get, Url: "/dashboard", Host: "acme.example.com"}))
+	require.Equal(t, "/users/{id:[0-9]+}{allow_slash:\\/?}", routes.All()[0].Uri())
+}
+
+func Test_load_route_cache_written_after_matching(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	written := cachedRoutes()
+	require.Equal(t, "user 12", callRoute(written, http.Options{Method: method.Get, Url: "/api/users/12"}))
+	require.Nil(t, written.WriteCache(path))
+
+	routes := cachedRoutes()
+
+	require.Nil(t, routes.LoadCache(path))
+	require.Equal(t, "user 12", callRoute(routes, http.Options{Method: method.Get, Url: "/api/users/12"}))
+}
+
+func Test_load_route_cache_keeps_constraints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	require.Nil(t, cachedRoutes().WriteCache(path))
+
+	routes := cachedRoutes()
+	require.Nil(t, routes.LoadCache(path))
+
+	request := newRequest(http.Options{Method: method.Get, Url: "/api/users/abc"})
+	route := routes.Match(request)
+	response := route.Controller()(request)
+	require.True(t, errors.Is(response.GetContent().(error), routing.RouteNotFoundError))
+}
+
+func Test_url_by_name_with_route_cache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	require.Nil(t, cachedRoutes().WriteCache(path))
+
+	routes := cachedRoutes()
+	require.Nil(t, routes.LoadCache(path))
+	app := newRequest(http.Options{}).App()
+	app.Singleton("routes", routes)
+
+	require.Equal(t, "/api/users/12", outcome.UrlByName(app, "user", outcome.Parameters{"id": 12}))
+}
+
+func Test_stale_route_cache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	require.Nil(t, cachedRoutes().WriteCache(path))
+
+	routes := routing.Group(
+		routing.Get("/users/{id}", cachedTenant).Name("user"),
+		routing.Get("/dashboard", cachedTenant).Name("tenant"),
+	)
+	err := routes.LoadCache(path)
+
+	require.True(t, errors.Is(err, routing.StaleRouteCacheError))
+	require.Equal(t, "/users/{id}", routes.All()[0].Uri())
+}
+
+func Test_route_cache_is_stale_when_constraint_changes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	require.Nil(t, cachedRoutes().WriteCache(path))
+
+	routes := routing.Group(
+		routing.Group(
+			routing.Get("/users/{id}", cachedUser).Name("user"),
+		).Prefix("/api").Where("id", "[a-z]+"),
+		routing.Get("/dashboard", cachedTenant).Domain("{tenant}.example.com").Name("tenant"),
+	)
+
+	require.True(t, errors.Is(routes.LoadCache(path), routing.StaleRouteCacheError))
+}
+
+func Test_route_cache_is_stale_when_prefix_changes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	require.Nil(t, cachedRoutes().WriteCache(path))
+
+	routes := routing.Group(
+		routing.Group(
+			routing.Get("/users/{id}", cachedUser).Name("user"),
+		).Prefix("/v2").Where("id", "[0-9]+"),
+		routing.Get("/dashboard", cachedTenant).Domain("{tenant}.example.com").Name("tenant"),
+	)
+
+	require.True(t, errors.Is(routes.LoadCache(path), routing.StaleRouteCacheError))
+}
+
+func Test_route_cache_is_stale_when_domain_changes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	require.Nil(t, cachedRoutes().WriteCache(path))
+
+	routes := routing.Group(
+		routing.Group(
+			routing.Get("/users/{id}", cachedUser).Name("user"),
+		).Prefix("/api").Where("id", "[0-9]+"),
+		routing.Get("/dashboard", cachedTenant).Domain("{tenant}.example.org").Name("tenant"),
+	)
+
+	require.True(t, errors.Is(routes.LoadCache(path), routing.StaleRouteCacheError))
+}
+
+func Test_route_service_provider_rebuilds_stale_cache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	require.Nil(t, cachedRoutes().WriteCache(path))
+	routes := routing.Group(
+		routing.Group(
+			routing.Get("/users/{id}", cachedUser).Name("user"),
+		).Prefix("/api").Where("id", "[a-z]+"),
+	)
+	container := inter.Container(foundation.NewContainer())
+
+	providers.RouteServiceProvider{Routes: routes, CachePath: path}.Register(container)
+
+	require.Equal(t, "user abc", callRoute(routes, http.Options{Method: method.Get, Url: "/api/users/abc"}))
+}
+
+func Test_missing_route_cache(t *testing.T) {
+	err := cachedRoutes().LoadCache(filepath.Join(t.TempDir(), "routes.json"))
+
+	require.True(t, errors.Is(err, routing.RouteCacheError))
+}
+
+func Test_invalid_routes_can_not_be_cached(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/users", cachedUser).Name("users"),
+		routing.Get("/users", cachedUser).Name("users"),
+	)
+
+	err := routes.WriteCache(filepath.Join(t.TempDir(), "routes.json"))
+
+	require.True(t, errors.Is(err, routing.RouteCacheError))
+}
+
+func Test_route_service_provider_loads_cache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	require.Nil(t, cachedRoutes().WriteCache(path))
+	// Prove that the compiled pattern of the cache is used
+	content, _ := ioutil.ReadFile(path)
+	content = bytes.ReplaceAll(content, []byte(`(?P\u003cv0\u003e[0-9]+)`), []byte(`(?P\u003cv0\u003e[a-z]+)`))
+	require.Nil(t, ioutil.WriteFile(path, content, 0644))
+	routes := cachedRoutes()
+	container := inter.Container(foundation.NewContainer())
+
+	providers.RouteServiceProvider{Routes: routes, CachePath: path}.Register(container)
+
+	require.Equal(t, "user abc", callRoute(routes, http.Options{Method: method.Get, Url: "/api/users/abc"}))
+}
+
+func callRoute(routes inter.RouteCollection, options http.Options) string {
+	request := newRequest(options)
+	response := routes.Match(request).Controller()(request)
+	response.SetApp(request.App())
+
+	return response.GetBody()
+}
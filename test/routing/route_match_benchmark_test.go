@@ -0,0 +1,55 @@
+package routing
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/http_helper"
+	"github.com/confetti-framework/foundation/http/method"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/gorilla/mux"
+	"strconv"
+	"testing"
+)
+
+// Compare the compiled routes with building a mux.Route per route per request
+// (as the route collection did before) on an application with 500 routes.
+
+func Benchmark_match_500_routes_compiled(b *testing.B) {
+	routes := benchmarkRoutes()
+	request := newRequest(http.Options{Method: method.Get, Url: "/resource499/12/comments"})
+	routes.Match(request)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		routes.Match(request)
+	}
+}
+
+func Benchmark_match_500_routes_mux_per_request(b *testing.B) {
+	routes := benchmarkRoutes().Compile()
+	request := newRequest(http.Options{Method: method.Get, Url: "/resource499/12/comments"})
+	source := request.Source()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, route := range routes.All() {
+			var match mux.RouteMatch
+			if http_helper.MuxFromRoute(route).Match(&source, &match) {
+				break
+			}
+		}
+	}
+}
+
+func benchmarkRoutes() *routing.RouteCollection {
+	var collections []inter.RouteCollection
+	for i := 0; i < 250; i++ {
+		resource := "/resource" + strconv.Itoa(i)
+		collections = append(collections,
+			routing.Get(resource+"/{id}", emptyController()).Where("id", "[0-9]+"),
+			routing.Get(resource+"/{id}/comments", emptyController()).Where("id", "[0-9]+"),
+		)
+	}
+
+	return routing.Group(collections...)
+}
@@ -0,0 +1,185 @@
+package routing
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/auth"
+	"github.com/confetti-framework/foundation/http/method"
+	"github.com/confetti-framework/foundation/http/middleware"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/stretchr/testify/require"
+	net "net/http"
+	"testing"
+)
+
+type user struct {
+	Id   string
+	Name string
+}
+
+type BlogPost struct {
+	Title string
+}
+
+func resolveUser(value string, _ inter.Request) (interface{}, error) {
+	if value != "12" {
+		return nil, routing.ModelNotFoundError
+	}
+	return user{Id: value, Name: "Janet"}, nil
+}
+
+func Test_route_model_binding_by_name(t *testing.T) {
+	routes := routing.Group(
+		routing.Bind("user", resolveUser),
+		routing.Get("/users/{user}", func(request inter.Request) inter.Response {
+			return outcome.Html(request.Parameter("user").Raw().(user).Name)
+		}),
+	)
+
+	response := matchAndCall(routes, "/users/12")
+
+	require.Equal(t, "Janet", response.GetBody())
+}
+
+func Test_route_model_binding_on_collection(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/users/{user}", func(request inter.Request) inter.Response {
+			return outcome.Html(request.Parameter("user").Raw().(user).Id)
+		}),
+	).Bind("user", resolveUser)
+
+	response := matchAndCall(routes, "/users/12")
+
+	require.Equal(t, "12", response.GetBody())
+}
+
+func Test_route_model_binding_by_type(t *testing.T) {
+	routes := routing.Group(
+		routing.BindType(BlogPost{}, func(value string, _ inter.Request) (interface{}, error) {
+			return BlogPost{Title: "Post " + value}, nil
+		}),
+		routing.Get("/posts/{blog_post}", func(request inter.Request) inter.Response {
+			return outcome.Html(request.Parameter("blog_post").Raw().(BlogPost).Title)
+		}),
+	)
+
+	response := matchAndCall(routes, "/posts/3")
+
+	require.Equal(t, "Post 3", response.GetBody())
+}
+
+type APIKey struct {
+	Key string
+}
+
+func Test_route_model_binding_by_type_with_acronym(t *testing.T) {
+	routes := routing.Group(
+		routing.BindType(&APIKey{}, func(value string, _ inter.Request) (interface{}, error) {
+			return APIKey{Key: value}, nil
+		}),
+		routing.Get("/keys/{api_key}", func(request inter.Request) inter.Response {
+			return outcome.Html(request.Parameter("api_key").Raw().(APIKey).Key)
+		}),
+	)
+
+	response := matchAndCall(routes, "/keys/abc")
+
+	require.Equal(t, "abc", response.GetBody())
+}
+
+func Test_route_model_binding_by_type_with_id_suffix(t *testing.T) {
+	type UserID struct{ Id string }
+	routes := routing.Group(
+		routing.BindType(UserID{}, func(value string, _ inter.Request) (interface{}, error) {
+			return UserID{Id: value}, nil
+		}),
+		routing.Get("/users/{user_id}", func(request inter.Request) inter.Response {
+			return outcome.Html(request.Parameter("user_id").Raw().(UserID).Id)
+		}),
+	)
+
+	response := matchAndCall(routes, "/users/12")
+
+	require.Equal(t, "12", response.GetBody())
+}
+
+func Test_route_model_binding_not_found(t *testing.T) {
+	routes := routing.Group(
+		routing.Bind("user", resolveUser),
+		routing.Get("/users/{user}", emptyController()),
+	)
+
+	response := matchAndCall(routes, "/users/13")
+
+	err := response.GetContent().(error)
+	status, _ := errors.FindStatus(err)
+	require.Equal(t, net.StatusNotFound, status)
+	require.True(t, errors.Is(err, routing.RouteNotFoundError))
+}
+
+func Test_route_model_binding_with_nil_result(t *testing.T) {
+	routes := routing.Group(
+		routing.Bind("user", func(value string, _ inter.Request) (interface{}, error) {
+			return nil, nil
+		}),
+		routing.Get("/users/{user}", emptyController()),
+	)
+
+	response := matchAndCall(routes, "/users/12")
+
+	require.True(t, errors.Is(response.GetContent().(error), routing.ModelNotFoundError))
+}
+
+func Test_route_model_binding_without_parameter(t *testing.T) {
+	routes := routing.Group(
+		routing.Bind("user", resolveUser),
+		routing.Get("/users", func(request inter.Request) inter.Response {
+			return outcome.Html("all users")
+		}),
+	)
+
+	response := matchAndCall(routes, "/users")
+
+	require.Equal(t, "all users", response.GetBody())
+}
+
+type guestGuard struct{}
+
+func (g guestGuard) User(_ inter.Request) (auth.User, error) {
+	return nil, errors.WithStack(auth.UnauthenticatedError)
+}
+
+func Test_route_model_binding_after_authentication(t *testing.T) {
+	resolved := false
+	routes := routing.Group(
+		routing.Bind("user", func(value string, request inter.Request) (interface{}, error) {
+			resolved = true
+			return resolveUser(value, request)
+		}),
+		routing.Get("/users/{user}", emptyController()),
+	).Middleware(middleware.Authenticate("web"))
+	request := newRequest(http.Options{Method: method.Get, Url: "/users/13"})
+	request.App().Bind("auth_guards", auth.Guards{"web": guestGuard{}})
+
+	response := dispatch(request, routes)
+
+	require.Equal(t, net.StatusUnauthorized, response.GetStatus())
+	require.False(t, resolved)
+}
+
+// matchAndCall dispatches the request, so the parameters are resolved by the
+// middlewares of the framework
+func matchAndCall(routes inter.RouteCollection, url string) inter.Response {
+	return dispatch(newRequest(http.Options{Method: method.Get, Url: url}), routes)
+}
+
+func dispatch(request inter.Request, routes inter.RouteCollection) inter.Response {
+	request.App().Bind("default_response_outcome", outcome.Html)
+	request.App().Singleton("routes", routes)
+	response := http.NewRouter(request.App()).DispatchToRoute(request)
+	response.SetApp(request.App())
+
+	return response
+}
@@ -0,0 +1,151 @@
+package routing
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/confetti-framework/foundation/providers"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_validate_valid_routes(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/users", emptyController()).Domain("api.confetti-framework.com"),
+		routing.Get("/users", emptyController()).Name("Users"),
+		routing.Get("/users/{id}", emptyController()).Name("User"),
+		routing.Post("/users", emptyController()).Name("UserStore"),
+		routing.Fallback(emptyController()),
+	)
+
+	require.Empty(t, routes.Validate())
+}
+
+func Test_validate_duplicate_routes(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/users", emptyController()),
+		routing.Get("/users", emptyController()),
+	)
+
+	problems := routes.Validate()
+
+	// GET and HEAD are both registered twice
+	require.Len(t, problems, 2)
+	require.True(t, errors.Is(problems[0], routing.DuplicateRouteError))
+	require.Contains(t, problems[0].Error(), "GET /users")
+}
+
+func Test_validate_duplicate_names(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/users", emptyController()).Name("Users"),
+		routing.Get("/roles", emptyController()).Name("Users"),
+	)
+
+	problems := routes.Validate()
+
+	require.Len(t, problems, 1)
+	require.True(t, errors.Is(problems[0], routing.DuplicateNameError))
+}
+
+func Test_validate_route_shadowed_by_fallback(t *testing.T) {
+	routes := routing.Group(
+		routing.Fallback(emptyController()),
+		routing.Get("/users/{id}", emptyController()),
+	)
+
+	problems := routes.Validate()
+
+	require.NotEmpty(t, problems)
+	require.True(t, errors.Is(problems[0], routing.UnreachableRouteError))
+	require.Contains(t, problems[0].Error(), "GET /users/{id} is shadowed by GET /{any:.*}")
+}
+
+func Test_validate_static_route_shadowed_by_parameter(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/users/{id}", emptyController()),
+		routing.Get("/users/active", emptyController()),
+	)
+
+	problems := routes.Validate()
+
+	require.Len(t, problems, 2)
+	require.True(t, errors.Is(problems[0], routing.UnreachableRouteError))
+}
+
+func Test_validate_static_route_not_shadowed_by_constraint(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/users/{id}", emptyController()).Where("id", "[0-9]+"),
+		routing.Get("/users/active", emptyController()),
+	)
+
+	require.Empty(t, routes.Validate())
+}
+
+func Test_validate_route_not_shadowed_by_other_domain(t *testing.T) {
+	routes := routing.Group(
+		routing.Fallback(emptyController()).Domain("api.confetti-framework.com"),
+		routing.Get("/users", emptyController()),
+	)
+
+	require.Empty(t, routes.Validate())
+}
+
+func Test_validate_route_with_domain_shadowed_by_route_without_domain(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/users", emptyController()),
+		routing.Get("/users", emptyController()).Domain("api.confetti-framework.com"),
+	)
+
+	problems := routes.Validate()
+
+	require.NotEmpty(t, problems)
+	require.Contains(t, problems[0].Error(), "GET api.confetti-framework.com/users is shadowed by GET /users")
+}
+
+func Test_validate_invalid_constraint(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/users/{id}", emptyController()).Where("id", "[0-9"),
+	)
+
+	problems := routes.Validate()
+
+	require.NotEmpty(t, problems)
+	require.True(t, errors.Is(problems[0], routing.InvalidConstraintError))
+}
+
+func Test_route_service_provider_binds_routes(t *testing.T) {
+	routes := routing.Group(routing.Get("/users", emptyController()))
+	container := inter.Container(foundation.NewContainer())
+	container.Bind("config.App.Debug", true)
+
+	container = providers.RouteServiceProvider{Routes: routes}.Register(container)
+
+	require.Equal(t, routes, container.Make("routes"))
+}
+
+func Test_route_service_provider_fails_in_debug_mode(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/users", emptyController()).Name("Users"),
+		routing.Get("/roles", emptyController()).Name("Users"),
+	)
+	container := inter.Container(foundation.NewContainer())
+	container.Bind("config.App.Debug", true)
+
+	require.Panics(t, func() {
+		providers.RouteServiceProvider{Routes: routes}.Register(container)
+	})
+}
+
+func Test_route_service_provider_without_debug_mode(t *testing.T) {
+	routes := routing.Group(
+		routing.Get("/users", emptyController()).Name("Users"),
+		routing.Get("/roles", emptyController()).Name("Users"),
+	)
+	container := inter.Container(foundation.NewContainer())
+	container.Bind("config.App.Debug", false)
+
+	container = providers.RouteServiceProvider{Routes: routes}.Register(container)
+
+	require.Equal(t, routes, container.Make("routes"))
+}
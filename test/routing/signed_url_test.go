@@ -0,0 +1,174 @@
+package routing
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/errors"
+	"github.com/confetti-framework/foundation"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/http_helper"
+	"github.com/confetti-framework/foundation/http/method"
+	"github.com/confetti-framework/foundation/http/middleware"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/confetti-framework/foundation/test/mock"
+	"github.com/stretchr/testify/require"
+	net "net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_signed_url_contains_signature_and_expires(t *testing.T) {
+	app := signedUrlApp()
+
+	url := outcome.SignedUrlByName(app, "Unsubscribe", outcome.Parameters{"user": 12}, time.Now().Add(time.Hour))
+
+	require.Contains(t, url, "https://confetti-framework.com/unsubscribe/12?")
+	require.Contains(t, url, "expires=")
+	require.Contains(t, url, "signature=")
+}
+
+func Test_signed_url_is_valid(t *testing.T) {
+	app := signedUrlApp()
+	url := outcome.SignedUrlByName(app, "Unsubscribe", outcome.Parameters{"user": 12}, time.Now().Add(time.Hour))
+
+	response := validateSignature(app, url)
+
+	require.Equal(t, net.StatusOK, response.GetStatus())
+	require.Equal(t, "unsubscribed", response.GetBody())
+}
+
+func Test_signed_url_without_expiration_is_valid(t *testing.T) {
+	app := signedUrlApp()
+	url := outcome.SignedUrlByName(app, "Unsubscribe", outcome.Parameters{"user": 12}, time.Time{})
+
+	response := validateSignature(app, url)
+
+	require.NotContains(t, url, "expires=")
+	require.Equal(t, net.StatusOK, response.GetStatus())
+}
+
+func Test_signed_url_with_query_parameters_is_valid(t *testing.T) {
+	app := signedUrlApp()
+	url := outcome.SignedUrlByName(
+		app,
+		"Unsubscribe",
+		outcome.Parameters{"user": 12},
+		time.Now().Add(time.Hour),
+		outcome.Parameters{"list": "news"},
+	)
+
+	response := validateSignature(app, url)
+
+	require.Contains(t, url, "list=news")
+	require.Equal(t, net.StatusOK, response.GetStatus())
+}
+
+func Test_signed_url_with_tampered_parameter(t *testing.T) {
+	app := signedUrlApp()
+	url := outcome.SignedUrlByName(app, "Unsubscribe", outcome.Parameters{"user": 12}, time.Now().Add(time.Hour))
+
+	response := validateSignature(app, strings.Replace(url, "/12?", "/13?", 1))
+
+	require.Equal(t, net.StatusForbidden, response.GetStatus())
+	require.True(t, errors.Is(response.GetContent().(error), middleware.InvalidSignatureError))
+}
+
+func Test_signed_url_without_signature(t *testing.T) {
+	app := signedUrlApp()
+
+	response := validateSignature(app, "https://confetti-framework.com/unsubscribe/12")
+
+	require.Equal(t, net.StatusForbidden, response.GetStatus())
+}
+
+func Test_signed_url_expired(t *testing.T) {
+	app := signedUrlApp()
+	url := outcome.SignedUrlByName(app, "Unsubscribe", outcome.Parameters{"user": 12}, time.Now().Add(-time.Minute))
+
+	response := validateSignature(app, url)
+
+	require.Equal(t, net.StatusForbidden, response.GetStatus())
+	require.True(t, errors.Is(response.GetContent().(error), middleware.ExpiredSignatureError))
+}
+
+func Test_signed_url_with_other_key(t *testing.T) {
+	app := signedUrlApp()
+	url := outcome.SignedUrlByName(app, "Unsubscribe", outcome.Parameters{"user": 12}, time.Now().Add(time.Hour))
+	app.Bind("config.App.Key", "other-key-for-signing-of-32-chars")
+
+	response := validateSignature(app, url)
+
+	require.Equal(t, net.StatusForbidden, response.GetStatus())
+}
+
+func Test_signed_url_without_key(t *testing.T) {
+	app := signedUrlApp()
+	app.Bind("config.App.Key", "")
+
+	require.PanicsWithValue(t, "URL cannot be signed because config.App.Key must be a string of at least 32 characters", func() {
+		outcome.SignedUrlByName(app, "Unsubscribe", outcome.Parameters{"user": 12}, time.Time{})
+	})
+}
+
+func Test_signed_url_with_short_key(t *testing.T) {
+	app := signedUrlApp()
+	app.Bind("config.App.Key", "short-key")
+
+	require.Panics(t, func() {
+		outcome.SignedUrlByName(app, "Unsubscribe", outcome.Parameters{"user": 12}, time.Time{})
+	})
+}
+
+func Test_signed_url_with_invalid_key_type(t *testing.T) {
+	app := signedUrlApp()
+	app.Bind("config.App.Key", 12345)
+
+	require.Panics(t, func() {
+		outcome.SignedUrlByName(app, "Unsubscribe", outcome.Parameters{"user": 12}, time.Time{})
+	})
+}
+
+func Test_validate_signature_without_key(t *testing.T) {
+	app := signedUrlApp()
+	app.Bind("config.App.Key", "")
+	// Signed with an empty key, as an attacker could do
+	signature := http_helper.Signature("", "/unsubscribe/12", url.Values{})
+
+	response := validateSignature(app, "https://confetti-framework.com/unsubscribe/12?signature="+signature)
+
+	require.Equal(t, net.StatusInternalServerError, response.GetStatus())
+	require.True(t, errors.Is(response.GetContent().(error), http_helper.InvalidAppKeyError))
+}
+
+func Test_validate_signature_with_invalid_key_type(t *testing.T) {
+	app := signedUrlApp()
+	app.Bind("config.App.Key", []byte("base-key-for-signing-of-32-chars"))
+
+	response := validateSignature(app, "https://confetti-framework.com/unsubscribe/12")
+
+	require.True(t, errors.Is(response.GetContent().(error), http_helper.InvalidAppKeyError))
+}
+
+func signedUrlApp() inter.App {
+	app := foundation.NewApp()
+	app.Bind("config.App.Key", "base-key-for-signing-of-32-chars")
+	app.Bind("outcome_html_encoders", mock.HtmlEncoders)
+	app.Bind("default_response_outcome", outcome.Html)
+	app.Singleton("routes", routing.Get("/unsubscribe/{user}", emptyController()).
+		Domain("confetti-framework.com").
+		Name("Unsubscribe"))
+
+	return app
+}
+
+func validateSignature(app inter.App, url string) inter.Response {
+	request := http.NewRequest(http.Options{App: app, Method: method.Get, Url: url})
+	response := middleware.ValidateSignature{}.Handle(request, func(request inter.Request) inter.Response {
+		return outcome.Html("unsubscribed")
+	})
+	response.SetApp(app)
+
+	return response
+}
@@ -0,0 +1,183 @@
+package routing
+
+import (
+	"bytes"
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/confetti-framework/foundation/loggers"
+	"github.com/confetti-framework/foundation/test/mock"
+	"github.com/confetti-framework/syslog/log_level"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	net "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type terminableMiddleware struct {
+	name       string
+	recorder   *httptest.ResponseRecorder
+	terminated *[]string
+}
+
+func (t terminableMiddleware) Handle(request inter.Request, next inter.Next) inter.Response {
+	return next(request)
+}
+
+func (t terminableMiddleware) Terminate(request inter.Request, response inter.Response) {
+	// The response must already be sent to the client
+	*t.terminated = append(*t.terminated, t.name+": "+t.recorder.Body.String())
+}
+
+func Test_terminable_middleware_after_response_is_sent(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	var terminated []string
+	app := newKernelApp()
+	app.Bind("global_middlewares", []inter.HttpMiddleware{
+		terminableMiddleware{name: "global", recorder: recorder, terminated: &terminated},
+	})
+	app.Singleton("routes", routing.Group(
+		routing.Get("/roles", func(request inter.Request) inter.Response {
+			return outcome.Html("roles")
+		}),
+	).Middleware(terminableMiddleware{name: "route", recorder: recorder, terminated: &terminated}))
+
+	http.HandleHttpKernel(app, recorder, httptest.NewRequest(net.MethodGet, "/roles", nil))
+
+	require.Equal(t, "roles", recorder.Body.String())
+	require.Equal(t, []string{"global: roles", "route: roles"}, terminated)
+}
+
+func Test_terminable_middleware_receives_response(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	var status int
+	app := newKernelApp()
+	app.Singleton("routes", routing.Group(
+		routing.Get("/roles", func(request inter.Request) inter.Response {
+			return outcome.Html("created").Status(net.StatusCreated)
+		}),
+	).Middleware(statusMiddleware{status: &status}))
+
+	http.HandleHttpKernel(app, recorder, httptest.NewRequest(net.MethodGet, "/roles", nil))
+
+	require.Equal(t, net.StatusCreated, status)
+}
+
+// blockingMiddleware terminates when the client received the response
+type blockingMiddleware struct {
+	received chan struct{}
+}
+
+func (b blockingMiddleware) Handle(request inter.Request, next inter.Next) inter.Response {
+	return next(request)
+}
+
+func (b blockingMiddleware) Terminate(request inter.Request, response inter.Response) {
+	<-b.received
+}
+
+func Test_client_receives_response_before_middlewares_are_terminated(t *testing.T) {
+	received := make(chan struct{})
+	server := httptest.NewServer(net.HandlerFunc(func(writer net.ResponseWriter, request *net.Request) {
+		app := newKernelApp()
+		app.Singleton("routes", routing.Get("/roles", func(request inter.Request) inter.Response {
+			return outcome.Html("roles")
+		}).Middleware(blockingMiddleware{received: received}))
+
+		http.HandleHttpKernel(app, writer, request)
+	}))
+	defer server.Close()
+	defer close(received)
+
+	response, err := net.Get(server.URL + "/roles")
+	require.NoError(t, err)
+	body := make(chan string)
+	go func() {
+		defer response.Body.Close()
+		raw, _ := ioutil.ReadAll(response.Body)
+		body <- string(raw)
+	}()
+
+	select {
+	case result := <-body:
+		require.Equal(t, "roles", result)
+		require.Equal(t, int64(5), response.ContentLength)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the response is not complete before the middlewares are terminated")
+	}
+}
+
+func Test_terminable_middleware_receives_response_without_body(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	var status int
+	app := newKernelApp()
+	app.Singleton("routes", routing.Group(
+		routing.Delete("/roles", func(request inter.Request) inter.Response {
+			return outcome.Html("").Status(net.StatusNoContent)
+		}),
+	).Middleware(statusMiddleware{status: &status}))
+
+	http.HandleHttpKernel(app, recorder, httptest.NewRequest(net.MethodDelete, "/roles", nil))
+
+	require.Equal(t, net.StatusNoContent, recorder.Code)
+	require.Empty(t, recorder.Header().Get("Content-Length"))
+	require.Equal(t, net.StatusNoContent, status)
+}
+
+type panickingMiddleware struct{}
+
+func (p panickingMiddleware) Handle(request inter.Request, next inter.Next) inter.Response {
+	return next(request)
+}
+
+func (p panickingMiddleware) Terminate(request inter.Request, response inter.Response) {
+	panic("metrics are unavailable")
+}
+
+func Test_panic_of_terminable_middleware_is_logged(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	output := &bytes.Buffer{}
+	var terminated []string
+	app := newKernelApp()
+	app.Bind("config.App.Name", "testing")
+	app.Bind("config.Logging.Default", "errors")
+	app.Bind("config.Logging.Channels", map[string]interface{}{
+		"errors": loggers.Syslog{Writer: output, MinLevel: log_level.DEBUG},
+	})
+	app.Singleton("routes", routing.Get("/roles", func(request inter.Request) inter.Response {
+		return outcome.Html("roles")
+	}).Middleware(panickingMiddleware{}, terminableMiddleware{name: "route", recorder: recorder, terminated: &terminated}))
+
+	require.NotPanics(t, func() {
+		http.HandleHttpKernel(app, recorder, httptest.NewRequest(net.MethodGet, "/roles", nil))
+	})
+
+	require.Equal(t, "roles", recorder.Body.String())
+	require.Equal(t, []string{"route: roles"}, terminated)
+	require.Contains(t, output.String(), "metrics are unavailable")
+	require.Contains(t, output.String(), `severity="err"`)
+}
+
+type statusMiddleware struct {
+	status *int
+}
+
+func (s statusMiddleware) Handle(request inter.Request, next inter.Next) inter.Response {
+	return next(request)
+}
+
+func (s statusMiddleware) Terminate(request inter.Request, response inter.Response) {
+	*s.status = response.GetStatus()
+}
+
+func newKernelApp() inter.App {
+	var app inter.App = foundation.NewApp()
+	app.Bind("outcome_html_encoders", mock.HtmlEncoders)
+	app.Bind("response_decorators", []inter.ResponseDecorator{})
+	app.Bind((*inter.HttpKernel)(nil), http.Kernel{App: &app})
+	return app
+}
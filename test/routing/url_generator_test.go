@@ -0,0 +1,158 @@
+package routing
+
+import (
+	"github.com/confetti-framework/contract/inter"
+	"github.com/confetti-framework/foundation"
+	"github.com/confetti-framework/foundation/http"
+	"github.com/confetti-framework/foundation/http/method"
+	"github.com/confetti-framework/foundation/http/outcome"
+	"github.com/confetti-framework/foundation/http/routing"
+	"github.com/confetti-framework/foundation/test/mock"
+	"github.com/stretchr/testify/require"
+	net "net/http"
+	"testing"
+)
+
+func Test_url_to_path_with_configured_root(t *testing.T) {
+	app := urlApp()
+	app.Bind("config.App.Url", "https://confetti-framework.com/")
+
+	url := outcome.Url(app).To("/users", outcome.Parameters{"page": 2})
+
+	require.Equal(t, "https://confetti-framework.com/users?page=2", url)
+}
+
+func Test_url_to_path_with_root_from_request(t *testing.T) {
+	app := urlApp()
+	urlRequest(app, "/users", nil)
+
+	require.Equal(t, "http://example.com/roles", outcome.Url(app).To("roles"))
+}
+
+func Test_url_to_absolute_url(t *testing.T) {
+	app := urlApp()
+
+	require.Equal(t, "https://other.com/users", outcome.Url(app).To("https://other.com/users"))
+}
+
+func Test_url_to_route(t *testing.T) {
+	app := urlApp()
+	app.Bind("config.App.Url", "https://confetti-framework.com")
+
+	url := outcome.Url(app).Route("user", outcome.Parameters{"id": 12})
+
+	require.Equal(t, "https://confetti-framework.com/users/12", url)
+}
+
+func Test_url_to_route_with_domain_and_forced_scheme(t *testing.T) {
+	app := urlApp()
+	app.Bind("config.App.Url", "http://localhost")
+
+	url := outcome.Url(app).Route("tenant", outcome.Parameters{"tenant": "acme"})
+
+	require.Equal(t, "http://acme.example.com/dashboard", url)
+}
+
+func Test_url_to_unknown_route(t *testing.T) {
+	app := urlApp()
+
+	_, err := outcome.Url(app).RouteE("unknown")
+
+	require.EqualError(t, err, "URL cannot be generated: no route found matching name unknown")
+	require.Panics(t, func() {
+		outcome.Url(app).Route("unknown")
+	})
+}
+
+func Test_url_current(t *testing.T) {
+	app := urlApp()
+	app.Bind("config.App.Url", "https://confetti-framework.com")
+	urlRequest(app, "/users?page=2", nil)
+
+	require.Equal(t, "https://confetti-framework.com/users?page=2", outcome.Url(app).Current())
+}
+
+func Test_url_previous(t *testing.T) {
+	app := urlApp()
+	app.Bind("config.App.Url", "https://confetti-framework.com")
+	urlRequest(app, "/users", net.Header{"Referer": {"https://confetti-framework.com/roles"}})
+
+	require.Equal(t, "https://confetti-framework.com/roles", outcome.Url(app).Previous())
+}
+
+func Test_url_previous_with_referer_of_other_host(t *testing.T) {
+	app := urlApp()
+	app.Bind("config.App.Url", "https://confetti-framework.com")
+	urlRequest(app, "/users", net.Header{"Referer": {"https://evil.example.com/phishing"}})
+
+	require.Equal(t, "https://confetti-framework.com/", outcome.Url(app).Previous())
+	require.Equal(t, "https://confetti-framework.com/home", outcome.Url(app).Previous("/home"))
+}
+
+func Test_url_previous_with_relative_referer(t *testing.T) {
+	app := urlApp()
+	app.Bind("config.App.Url", "https://confetti-framework.com")
+	urlRequest(app, "/users", net.Header{"Referer": {"//evil.example.com/phishing"}})
+
+	require.Equal(t, "https://confetti-framework.com/", outcome.Url(app).Previous())
+}
+
+func Test_url_previous_without_referer(t *testing.T) {
+	app := urlApp()
+	app.Bind("config.App.Url", "https://confetti-framework.com")
+	urlRequest(app, "/users", nil)
+
+	require.Equal(t, "https://confetti-framework.com/", outcome.Url(app).Previous())
+	require.Equal(t, "https://confetti-framework.com/home", outcome.Url(app).Previous("/home"))
+}
+
+func Test_url_asset(t *testing.T) {
+	app := urlApp()
+	app.Bind("config.App.Url", "https://confetti-framework.com")
+
+	require.Equal(t, "https://confetti-framework.com/css/app.css", outcome.Url(app).Asset("css/app.css"))
+
+	app.Bind("config.App.AssetUrl", "https://cdn.confetti-framework.com/")
+	require.Equal(t, "https://cdn.confetti-framework.com/css/app.css", outcome.Url(app).Asset("/css/app.css"))
+}
+
+func Test_url_generator_from_container(t *testing.T) {
+	app := urlApp()
+	generator := outcome.NewUrlGenerator(app)
+	app.Bind("url", generator)
+
+	require.Same(t, generator, outcome.Url(app))
+}
+
+func Test_url_generator_bound_once_per_request(t *testing.T) {
+	// The container of a request is created with the container of boot
+	app := foundation.NewTestApp(func(container inter.Container) inter.Container {
+		container.Bind("outcome_html_encoders", mock.HtmlEncoders)
+		container.Bind("response_decorators", []inter.ResponseDecorator{})
+		return container
+	})
+	request := http.NewRequest(http.Options{App: app, Method: method.Get, Url: "/users/1"})
+	request.App().Singleton("routes", routing.Get("/users/{id}", func(request inter.Request) inter.Response {
+		require.Same(t, outcome.Url(request.App()), outcome.Url(request.App()))
+		return outcome.Html(outcome.Url(request.App()).Current())
+	}))
+
+	response := http.Kernel{}.Handle(request)
+
+	require.Equal(t, "http://example.com/users/1", response.GetBody())
+}
+
+func urlApp() inter.App {
+	app := foundation.NewApp()
+	app.Singleton("routes", routing.Group(
+		routing.Get("/users/{id}", emptyController()).Name("user"),
+		routing.Get("/dashboard", emptyController()).Domain("{tenant}.example.com").Name("tenant"),
+	))
+
+	return app
+}
+
+func urlRequest(app inter.App, url string, header net.Header) {
+	request := http.NewRequest(http.Options{App: app, Method: method.Get, Url: url, Host: "example.com", Header: header})
+	app.Bind("request", request)
+}